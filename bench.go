@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/go-rq/req/internal/bench"
+	"github.com/go-rq/req/internal/httpfile"
+	"github.com/go-rq/req/internal/tui"
+	"github.com/rivo/tview"
+)
+
+// histogramWidth is the width of the histogram bars printed by req bench.
+const histogramWidth = 40
+
+// benchCommand implements `req bench`, which sends a request of a file
+// concurrently and prints its throughput and latency distribution.
+func benchCommand(args []string) int {
+	flags := newFlagSet("bench", "[flags] <file> [--name <request>]")
+	var (
+		name    string
+		live    bool
+		options bench.Options
+	)
+	flags.StringVar(&name, "name", "", "name of the request to send, may be omitted if the file has a single request")
+	flags.IntVar(&options.Concurrency, "c", 10, "number of workers sending at the same time")
+	flags.IntVar(&options.Requests, "n", 200, "total number of requests to send, unlimited if only -d is given")
+	flags.DurationVar(&options.Duration, "d", 0, "how long to send requests for, e.g. 30s")
+	flags.IntVar(&options.RPS, "rps", 0, "maximum number of requests per second across all workers")
+	flags.BoolVar(&live, "live", false, "show the results in a live view while the benchmark is running")
+	arguments, err := parseFlags(flags, args)
+	if err != nil {
+		return fail("bench", err)
+	}
+	if len(arguments) != 1 {
+		flags.Usage()
+		return 2
+	}
+	if options.RPS < 0 || options.RPS > int(time.Second) {
+		return fail("bench", fmt.Errorf("--rps must be between 0 and %d", int(time.Second)))
+	}
+	set := map[string]bool{}
+	flags.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+	if set["d"] && !set["n"] {
+		options.Requests = 0
+	}
+	request, err := benchRequest(arguments[0], name)
+	if err != nil {
+		return fail("bench", err)
+	}
+	// keep a connection per worker instead of reconnecting for every send
+	transportSettings.MaxIdleConnsPerHost = max(options.Concurrency, 1)
+	ctx, err := newContext()
+	if err != nil {
+		return fail("bench", err)
+	}
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	recorder := bench.NewRecorder()
+	if !live {
+		fmt.Fprintf(os.Stderr, "Sending %s with %d workers...\n", request.DisplayName(), options.Concurrency)
+		bench.Run(ctx, request, options, recorder)
+		recorder.Summary().Write(os.Stdout, histogramWidth)
+		return 0
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		bench.Run(ctx, request, options, recorder)
+	}()
+	app := tview.NewApplication()
+	tui.NewBenchView(app, "Bench "+request.DisplayName(), recorder, cancel).Mount(app)
+	if err := app.Run(); err != nil {
+		return fail("bench", err)
+	}
+	cancel()
+	<-done
+	recorder.Summary().Write(os.Stdout, histogramWidth)
+	return 0
+}
+
+// benchRequest returns the request with the given name from the file, or
+// its only request if no name is given.
+func benchRequest(path, name string) (httpfile.Request, error) {
+	requests, err := httpfile.ParseFile(path)
+	if err != nil {
+		return httpfile.Request{}, err
+	}
+	var names []string
+	for _, request := range requests {
+		if request.DisplayName() == name || name == "" && len(requests) == 1 {
+			return request, nil
+		}
+		names = append(names, request.DisplayName())
+	}
+	if name == "" {
+		return httpfile.Request{}, fmt.Errorf("%s has %d requests, choose one with --name: %s", path, len(requests), strings.Join(names, ", "))
+	}
+	return httpfile.Request{}, fmt.Errorf("no request named %q in %s", name, path)
+}
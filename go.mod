@@ -5,8 +5,10 @@ go 1.21.3
 require (
 	github.com/alecthomas/chroma v0.10.0
 	github.com/atotto/clipboard v0.1.4
+	github.com/dop251/goja v0.0.0-20231027120936-b396bb4c349d
 	github.com/gdamore/tcell/v2 v2.7.0
 	github.com/go-rq/rq v0.4.0
+	github.com/itchyny/gojq v0.12.13
 	github.com/rivo/tview v0.0.0-20231126152417-33a1d271f2b6
 	github.com/sahilm/fuzzy v0.1.0
 	github.com/samber/lo v1.39.0
@@ -14,10 +16,10 @@ require (
 
 require (
 	github.com/dlclark/regexp2 v1.10.0 // indirect
-	github.com/dop251/goja v0.0.0-20231027120936-b396bb4c349d // indirect
 	github.com/gdamore/encoding v1.0.0 // indirect
 	github.com/go-sourcemap/sourcemap v2.1.3+incompatible // indirect
 	github.com/google/pprof v0.0.0-20231205033806-a5a03c77bf08 // indirect
+	github.com/itchyny/timefmt-go v0.1.5 // indirect
 	github.com/kylelemons/godebug v1.1.0 // indirect
 	github.com/lucasb-eyer/go-colorful v1.2.0 // indirect
 	github.com/mattn/go-runewidth v0.0.15 // indirect
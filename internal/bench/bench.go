@@ -0,0 +1,147 @@
+// Package bench sends a request concurrently to measure the throughput and
+// latency of an endpoint:
+//
+//	req bench api.http --name "Get User" -c 50 -n 10000
+//
+// Every worker sends with its own copy of the environment so variables set
+// by the scripts of the request do not race, and its own cache of
+// prerequisites so their scripts set their variables in that copy.
+package bench
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-rq/req/internal/client"
+	"github.com/go-rq/req/internal/httpfile"
+	"github.com/go-rq/rq"
+)
+
+// Options configure a benchmark. It stops after Requests sends or once
+// Duration has passed, whichever comes first.
+type Options struct {
+	// Concurrency is the number of workers sending at the same time.
+	Concurrency int
+
+	// Requests is the total number of sends, zero for no limit.
+	Requests int
+
+	// Duration limits how long requests are sent, zero for no limit.
+	Duration time.Duration
+
+	// RPS limits the sends per second across all workers, zero for no limit.
+	RPS int
+}
+
+// Sample is the outcome of a single send.
+type Sample struct {
+	Latency    time.Duration
+	StatusCode int
+	Err        error
+}
+
+// Recorder collects the samples of a benchmark. It is safe for concurrent
+// use, so a summary can be taken while the benchmark is running.
+type Recorder struct {
+	mu      sync.Mutex
+	samples []Sample
+	start   time.Time
+	end     time.Time
+}
+
+func NewRecorder() *Recorder {
+	return &Recorder{start: time.Now()}
+}
+
+func (r *Recorder) add(sample Sample) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.samples = append(r.samples, sample)
+}
+
+func (r *Recorder) finish() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.end = time.Now()
+}
+
+// Summary summarizes the samples recorded so far.
+func (r *Recorder) Summary() Summary {
+	r.mu.Lock()
+	samples := slices.Clone(r.samples)
+	end := r.end
+	r.mu.Unlock()
+	done := !end.IsZero()
+	if !done {
+		end = time.Now()
+	}
+	return summarize(samples, end.Sub(r.start), done)
+}
+
+// Run sends the request with the client of the context until the options
+// say to stop or the context is cancelled, recording every send.
+func Run(ctx context.Context, request httpfile.Request, options Options, recorder *Recorder) {
+	defer recorder.finish()
+	if options.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, options.Duration)
+		defer cancel()
+	}
+	// the body is not saved for every send
+	request.Output = httpfile.Output{}
+	var limiter <-chan time.Time
+	if options.RPS > 0 {
+		ticker := time.NewTicker(time.Second / time.Duration(options.RPS))
+		defer ticker.Stop()
+		limiter = ticker.C
+	}
+	var (
+		sent int64
+		wg   sync.WaitGroup
+		env  = rq.GetEnvironment(ctx)
+	)
+	for i := 0; i < max(options.Concurrency, 1); i++ {
+		wg.Add(1)
+		go func(ctx context.Context) {
+			defer wg.Done()
+			for {
+				if options.Requests > 0 && atomic.AddInt64(&sent, 1) > int64(options.Requests) {
+					return
+				}
+				if limiter != nil {
+					select {
+					case <-ctx.Done():
+						return
+					case <-limiter:
+					}
+				}
+				if ctx.Err() != nil {
+					return
+				}
+				request := request
+				start := time.Now()
+				result, err := client.GetClient(ctx).Send(ctx, &request)
+				if ctx.Err() != nil {
+					// interrupted by the end of the benchmark
+					return
+				}
+				// prerequisites sent before the request are not part of its latency
+				for _, prerequisite := range result.Prerequisites {
+					if !prerequisite.Cached && prerequisite.SentAt.After(start) {
+						start = prerequisite.SentAt
+					}
+				}
+				sample := Sample{Latency: time.Since(start), Err: err}
+				if result.Response != nil {
+					sample.StatusCode = result.Response.StatusCode
+				}
+				recorder.add(sample)
+			}
+		}(client.WithResultCache(rq.WithEnvironment(ctx, maps.Clone(env))))
+	}
+	wg.Wait()
+}
@@ -0,0 +1,169 @@
+package bench
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// histogramBuckets is the number of buckets latencies are grouped in.
+const histogramBuckets = 10
+
+// Summary is the throughput, latency distribution and outcomes of the
+// sends of a benchmark.
+type Summary struct {
+	Requests int
+	Elapsed  time.Duration
+	Done     bool
+
+	// Statuses counts the responses by status code, Errors the sends that
+	// failed without a response by error message.
+	Statuses map[int]int
+	Errors   map[string]int
+
+	Min, Mean, P50, P90, P99, Max time.Duration
+
+	Histogram []Bucket
+}
+
+// Bucket counts the latencies up to Upper and above the upper bound of the
+// previous bucket.
+type Bucket struct {
+	Upper time.Duration
+	Count int
+}
+
+func summarize(samples []Sample, elapsed time.Duration, done bool) Summary {
+	summary := Summary{
+		Requests: len(samples),
+		Elapsed:  elapsed,
+		Done:     done,
+		Statuses: map[int]int{},
+		Errors:   map[string]int{},
+	}
+	if len(samples) == 0 {
+		return summary
+	}
+	latencies := make([]time.Duration, len(samples))
+	var total time.Duration
+	for i, sample := range samples {
+		latencies[i] = sample.Latency
+		total += sample.Latency
+		if sample.Err != nil {
+			summary.Errors[sample.Err.Error()]++
+		} else {
+			summary.Statuses[sample.StatusCode]++
+		}
+	}
+	slices.Sort(latencies)
+	summary.Min, summary.Max = latencies[0], latencies[len(latencies)-1]
+	summary.Mean = total / time.Duration(len(latencies))
+	summary.P50 = percentile(latencies, 50)
+	summary.P90 = percentile(latencies, 90)
+	summary.P99 = percentile(latencies, 99)
+	summary.Histogram = histogram(latencies)
+	return summary
+}
+
+// percentile returns the nearest-rank percentile of the sorted latencies.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	return sorted[max(rank-1, 0)]
+}
+
+// histogram groups the sorted latencies in buckets of equal width between
+// the minimum and maximum.
+func histogram(sorted []time.Duration) []Bucket {
+	lowest, highest := sorted[0], sorted[len(sorted)-1]
+	width := (highest - lowest) / histogramBuckets
+	if width == 0 {
+		return []Bucket{{Upper: highest, Count: len(sorted)}}
+	}
+	buckets := make([]Bucket, histogramBuckets)
+	for i := range buckets {
+		buckets[i].Upper = lowest + width*time.Duration(i+1)
+	}
+	buckets[len(buckets)-1].Upper = highest
+	i := 0
+	for _, latency := range sorted {
+		for latency > buckets[i].Upper {
+			i++
+		}
+		buckets[i].Count++
+	}
+	return buckets
+}
+
+// Throughput returns the number of sends per second.
+func (s Summary) Throughput() float64 {
+	if s.Elapsed <= 0 {
+		return 0
+	}
+	return float64(s.Requests) / s.Elapsed.Seconds()
+}
+
+// Write prints the summary as text, with a histogram whose bars are at most
+// width characters wide.
+func (s Summary) Write(w io.Writer, width int) {
+	fmt.Fprintf(w, "Requests:    %d in %s\n", s.Requests, s.Elapsed.Round(time.Millisecond))
+	fmt.Fprintf(w, "Throughput:  %.1f req/s\n", s.Throughput())
+	if s.Requests == 0 {
+		return
+	}
+	fmt.Fprintf(w, "Latency:     min %s, mean %s, p50 %s, p90 %s, p99 %s, max %s\n",
+		round(s.Min), round(s.Mean), round(s.P50), round(s.P90), round(s.P99), round(s.Max))
+
+	fmt.Fprintln(w, "\nStatus codes:")
+	codes := make([]int, 0, len(s.Statuses))
+	for code := range s.Statuses {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	for _, code := range codes {
+		fmt.Fprintf(w, "  %d  %8d  %5.1f%%\n", code, s.Statuses[code], s.percent(s.Statuses[code]))
+	}
+	if len(s.Errors) > 0 {
+		fmt.Fprintln(w, "\nErrors:")
+		messages := make([]string, 0, len(s.Errors))
+		for message := range s.Errors {
+			messages = append(messages, message)
+		}
+		sort.Slice(messages, func(i, j int) bool {
+			if s.Errors[messages[i]] != s.Errors[messages[j]] {
+				return s.Errors[messages[i]] > s.Errors[messages[j]]
+			}
+			return messages[i] < messages[j]
+		})
+		for _, message := range messages {
+			fmt.Fprintf(w, "  %8d  %5.1f%%  %s\n", s.Errors[message], s.percent(s.Errors[message]), message)
+		}
+	}
+
+	fmt.Fprintln(w, "\nHistogram:")
+	most := 0
+	for _, bucket := range s.Histogram {
+		most = max(most, bucket.Count)
+	}
+	for _, bucket := range s.Histogram {
+		bar := strings.Repeat("■", bucket.Count*width/most)
+		fmt.Fprintf(w, "  %10s  %8d  %s\n", round(bucket.Upper), bucket.Count, bar)
+	}
+}
+
+func (s Summary) percent(count int) float64 {
+	return float64(count) * 100 / float64(s.Requests)
+}
+
+// round rounds latencies to a precision that is readable at their scale.
+func round(d time.Duration) time.Duration {
+	switch {
+	case d >= time.Second:
+		return d.Round(time.Millisecond)
+	case d >= time.Millisecond:
+		return d.Round(10 * time.Microsecond)
+	}
+	return d.Round(time.Microsecond)
+}
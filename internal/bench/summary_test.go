@@ -0,0 +1,74 @@
+package bench
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestPercentile(t *testing.T) {
+	oneToTen := []time.Duration{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	tests := []struct {
+		name   string
+		sorted []time.Duration
+		p      int
+		want   time.Duration
+	}{
+		{"single sample", []time.Duration{7}, 99, 7},
+		{"p50 of ten", oneToTen, 50, 5},
+		{"p90 of ten", oneToTen, 90, 9},
+		{"p99 of ten", oneToTen, 99, 10},
+		{"p100 of ten", oneToTen, 100, 10},
+		{"rank rounded up", []time.Duration{1, 2, 3}, 50, 2},
+		{"p1 of three", []time.Duration{1, 2, 3}, 1, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := percentile(tt.sorted, tt.p); got != tt.want {
+				t.Errorf("percentile(%v, %d) = %d, want %d", tt.sorted, tt.p, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	tests := []struct {
+		name   string
+		sorted []time.Duration
+		want   []Bucket
+	}{
+		{
+			name:   "width 0",
+			sorted: []time.Duration{5, 5, 5},
+			want:   []Bucket{{Upper: 5, Count: 3}},
+		},
+		{
+			name:   "range below the number of buckets",
+			sorted: []time.Duration{1, 3, 9},
+			want:   []Bucket{{Upper: 9, Count: 3}},
+		},
+		{
+			name:   "upper bounds are inclusive",
+			sorted: []time.Duration{0, 10, 11, 20, 21, 100},
+			want: []Bucket{
+				{10, 2}, {20, 2}, {30, 1}, {40, 0}, {50, 0},
+				{60, 0}, {70, 0}, {80, 0}, {90, 0}, {100, 1},
+			},
+		},
+		{
+			name:   "last bucket clamped to the maximum",
+			sorted: []time.Duration{0, 10, 11, 100, 101, 105},
+			want: []Bucket{
+				{10, 2}, {20, 1}, {30, 0}, {40, 0}, {50, 0},
+				{60, 0}, {70, 0}, {80, 0}, {90, 0}, {105, 3},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := histogram(tt.sorted); !slices.Equal(got, tt.want) {
+				t.Errorf("histogram(%v) = %v, want %v", tt.sorted, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,268 @@
+// Package client sends requests parsed from .http files. It wraps the
+// http.Client handed to rq so that the settings configured on the command
+// line, in the TUI or with request directives are applied to every send.
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-rq/req/internal/httpfile"
+	"github.com/go-rq/req/internal/template"
+	"github.com/go-rq/rq"
+)
+
+type clientContextKey struct{}
+
+// Settings are the send options shared by every request in a session.
+// Request directives override them for a single request.
+type Settings struct {
+	// Timeout limits each attempt, including reading the response body.
+	// Zero means no timeout.
+	Timeout time.Duration
+
+	// Retries is the number of times a failed attempt is retried.
+	Retries int
+
+	// RetryOn lists the failures that are retried.
+	RetryOn RetryConditions
+
+	// Backoff is the delay before the first retry. It doubles for each
+	// following retry.
+	Backoff time.Duration
+
+	// MaxRedirects is the number of redirects followed before giving up.
+	MaxRedirects int
+
+	// SendReferences sends the requests referenced with
+	// {{Name.response...}} that have no response yet.
+	SendReferences bool
+
+	// DependencyTTL is how long the result of a request is reused when it is
+	// a prerequisite of another request. Zero sends prerequisites every time.
+	DependencyTTL time.Duration
+}
+
+// DefaultSettings returns the settings used when none are configured.
+func DefaultSettings() Settings {
+	return Settings{
+		RetryOn:        RetryConditions{RetryOn5xx, RetryOnConnect},
+		Backoff:        500 * time.Millisecond,
+		MaxRedirects:   10,
+		SendReferences: true,
+		DependencyTTL:  5 * time.Minute,
+	}
+}
+
+// Set updates the setting with the given key, using the same names as the
+// command line flags.
+func (s *Settings) Set(key, value string) error {
+	var err error
+	switch key {
+	case "timeout":
+		s.Timeout, err = time.ParseDuration(value)
+	case "retries":
+		s.Retries, err = strconv.Atoi(value)
+	case "retry-on":
+		err = s.RetryOn.Set(value)
+	case "backoff":
+		s.Backoff, err = time.ParseDuration(value)
+	case "max-redirects":
+		s.MaxRedirects, err = strconv.Atoi(value)
+	case "send-references":
+		s.SendReferences, err = strconv.ParseBool(value)
+	case "dependency-ttl":
+		s.DependencyTTL, err = time.ParseDuration(value)
+	default:
+		return fmt.Errorf("unknown setting %q", key)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return nil
+}
+
+// String returns the settings as key=value lines.
+func (s Settings) String() string {
+	return strings.Join([]string{
+		fmt.Sprintf("timeout=%s", s.Timeout),
+		fmt.Sprintf("retries=%d", s.Retries),
+		fmt.Sprintf("retry-on=%s", s.RetryOn),
+		fmt.Sprintf("backoff=%s", s.Backoff),
+		fmt.Sprintf("max-redirects=%d", s.MaxRedirects),
+		fmt.Sprintf("send-references=%t", s.SendReferences),
+		fmt.Sprintf("dependency-ttl=%s", s.DependencyTTL),
+	}, "\n")
+}
+
+// Client sends requests with the configured settings.
+type Client struct {
+	Settings Settings
+	http     *http.Client
+	jar      *Jar
+	cache    *resultCache
+}
+
+// New returns a client that stores cookies in the given jar, which is shared
+// by every request sent with the client. A nil jar keeps the cookies in
+// memory only.
+func New(settings Settings, transportSettings TransportSettings, jar *Jar) (*Client, error) {
+	transport, err := transportSettings.transport()
+	if err != nil {
+		return nil, err
+	}
+	if jar == nil {
+		jar, _ = NewJar("")
+	}
+	return &Client{
+		Settings: settings,
+		http:     &http.Client{Transport: transport, Jar: jar},
+		jar:      jar,
+		cache:    newResultCache(),
+	}, nil
+}
+
+// Jar returns the cookie jar of the client.
+func (c *Client) Jar() *Jar {
+	return c.jar
+}
+
+// WithClient returns a new context with the given client.
+func WithClient(ctx context.Context, client *Client) context.Context {
+	return context.WithValue(ctx, clientContextKey{}, client)
+}
+
+// GetClient returns the client stored in the context, or a client with the
+// default settings if there is none.
+func GetClient(ctx context.Context) *Client {
+	if client, ok := ctx.Value(clientContextKey{}).(*Client); ok {
+		return client
+	}
+	jar, _ := NewJar("")
+	return &Client{
+		Settings: DefaultSettings(),
+		http:     &http.Client{Jar: jar},
+		jar:      jar,
+		cache:    newResultCache(),
+	}
+}
+
+// Result is the outcome of sending a request.
+type Result struct {
+	Response *rq.Response
+
+	// Attempts holds every attempt made, in order. The last attempt is the
+	// one the response or error came from.
+	Attempts []Attempt
+
+	// Variables are the values the dynamic expressions of the request
+	// resolved to.
+	Variables []template.Variable
+
+	// Output is the file the response body was saved to for a request with a
+	// `>> path` line, OutputErr the reason it could not be saved.
+	Output    string
+	OutputErr error
+
+	// Prerequisites are the requests the request depends on, in the order
+	// they were sent or taken from the cache.
+	Prerequisites []Prerequisite
+
+	// Polls are the responses received while polling a request declared with
+	// `# @poll`, the last of which is Response.
+	Polls []Poll
+}
+
+// Send executes the prerequisites of the request and then the request
+// itself, see send, polling it if it declares `# @poll`. The returned result
+// is never nil so the attempts made are available even when the send fails.
+func (c *Client) Send(ctx context.Context, request *httpfile.Request) (*Result, error) {
+	prerequisites, err := c.sendPrerequisites(ctx, request)
+	if err != nil {
+		return &Result{Prerequisites: prerequisites}, err
+	}
+	var result *Result
+	if poll, ok := request.Poll(); ok {
+		result, err = c.poll(ctx, request, poll)
+	} else {
+		result, err = c.send(ctx, request)
+	}
+	result.Prerequisites = prerequisites
+	return result, err
+}
+
+// send executes the request, applying the request directives on top of the
+// client settings and resolving its template expressions, and saves the
+// response body if the request redirects it to a file. Successful results
+// are cached for requests depending on the request.
+func (c *Client) send(ctx context.Context, request *httpfile.Request) (*Result, error) {
+	settings := c.Settings
+	if timeout, ok := request.Timeout(); ok {
+		settings.Timeout = timeout
+	}
+	if retries, ok := request.Retries(); ok {
+		settings.Retries = retries
+	}
+	httpClient := c.http
+	if request.NoCookieJar() {
+		httpClient = &http.Client{Transport: c.http.Transport}
+	}
+	resolver := template.New(ctx, request)
+	resolver.SendReferences = settings.SendReferences
+	resolved, err := resolver.Request(request.Request)
+	if err != nil {
+		return &Result{}, err
+	}
+	runner := &runner{client: httpClient, settings: settings, noRedirect: request.NoRedirect(), request: request}
+	resp, err := resolved.Do(rq.WithRequestRunner(ctx, runner))
+	request.Logs, request.PreRequestAssertions = resolved.Logs, resolved.PreRequestAssertions
+	result := &Result{Response: resp, Attempts: runner.attempts, Variables: resolver.Variables}
+	if err != nil && len(runner.attempts) > 1 {
+		err = fmt.Errorf("%w (gave up after %d attempts)", err, len(runner.attempts))
+	}
+	if err == nil {
+		c.writeOutput(ctx, request, result)
+		c.resultCache(ctx).put(request, result)
+	}
+	return result, err
+}
+
+// writeOutput saves the response body of the result if the request redirects
+// it to a file.
+func (c *Client) writeOutput(ctx context.Context, request *httpfile.Request, result *Result) {
+	// expanded after the request so variables set by its scripts apply
+	if path, ok := request.OutputPath(rq.GetEnvironment(ctx)); ok {
+		result.Output, result.OutputErr = saveOutput(path, request.Output.Overwrite, result.Response)
+	}
+}
+
+// encodeBody replaces the body of req with the body encoded for sending, see
+// httpfile.Request.EncodeBody.
+func encodeBody(req *http.Request, request *httpfile.Request) error {
+	contentType := req.Header.Get("Content-Type")
+	if req.Body == nil || len(request.BodyFiles) == 0 && !strings.HasPrefix(strings.ToLower(contentType), "multipart/") {
+		return nil
+	}
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+	req.Body.Close()
+	body, contentType, err = request.EncodeBody(contentType, body, rq.GetEnvironment(req.Context()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.ContentLength = int64(len(body))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	req.Body, _ = req.GetBody()
+	return nil
+}
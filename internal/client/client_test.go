@@ -0,0 +1,41 @@
+package client
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSettingsStringParsesBack(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings Settings
+	}{
+		{"defaults", DefaultSettings()},
+		{"no retry conditions", Settings{Retries: 2, MaxRedirects: 3}},
+		{"every setting", Settings{
+			Timeout:        10 * time.Second,
+			Retries:        3,
+			RetryOn:        RetryConditions{RetryOn5xx, RetryOnConnect, RetryOnTimeout},
+			Backoff:        time.Second,
+			MaxRedirects:   5,
+			SendReferences: true,
+			DependencyTTL:  time.Minute,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var parsed Settings
+			for _, line := range strings.Split(tt.settings.String(), "\n") {
+				key, value, _ := strings.Cut(line, "=")
+				if err := parsed.Set(key, value); err != nil {
+					t.Fatalf("Set(%q, %q): %v", key, value, err)
+				}
+			}
+			if !reflect.DeepEqual(parsed, tt.settings) {
+				t.Errorf("parsed %+v, want %+v", parsed, tt.settings)
+			}
+		})
+	}
+}
@@ -0,0 +1,293 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cookie is a cookie stored in a Jar.
+type Cookie struct {
+	Name     string    `json:"name"`
+	Value    string    `json:"value"`
+	Domain   string    `json:"domain"`
+	Path     string    `json:"path"`
+	Expires  time.Time `json:"expires,omitempty"`
+	HostOnly bool      `json:"hostOnly,omitempty"`
+	Secure   bool      `json:"secure,omitempty"`
+	HttpOnly bool      `json:"httpOnly,omitempty"`
+}
+
+// String returns the cookie in Set-Cookie syntax.
+func (c Cookie) String() string {
+	parts := []string{fmt.Sprintf("%s=%s", c.Name, c.Value)}
+	if !c.HostOnly {
+		parts = append(parts, "Domain="+c.Domain)
+	}
+	parts = append(parts, "Path="+c.Path)
+	if !c.Expires.IsZero() {
+		parts = append(parts, "Expires="+c.Expires.UTC().Format(http.TimeFormat))
+	}
+	if c.Secure {
+		parts = append(parts, "Secure")
+	}
+	if c.HttpOnly {
+		parts = append(parts, "HttpOnly")
+	}
+	return strings.Join(parts, "; ")
+}
+
+func (c Cookie) expired(now time.Time) bool {
+	return !c.Expires.IsZero() && !c.Expires.After(now)
+}
+
+func (c Cookie) matches(u *url.URL) bool {
+	host := canonicalHost(u.Host)
+	if c.HostOnly && host != c.Domain {
+		return false
+	}
+	if !c.HostOnly && !domainMatch(host, c.Domain) {
+		return false
+	}
+	if c.Secure && u.Scheme != "https" {
+		return false
+	}
+	return pathMatch(requestPath(u), c.Path)
+}
+
+type cookieKey struct {
+	domain, path, name string
+}
+
+func (c Cookie) key() cookieKey {
+	return cookieKey{domain: c.Domain, path: c.Path, name: c.Name}
+}
+
+// Jar is an http.CookieJar shared by all requests in a session. Unlike
+// net/http/cookiejar its cookies can be listed and changed, and it can be
+// persisted to a file so a session survives restarts.
+type Jar struct {
+	mu      sync.Mutex
+	path    string
+	cookies map[cookieKey]Cookie
+}
+
+// NewJar returns a jar persisted to the file at path, loading the cookies
+// already saved in it. An empty path keeps the cookies in memory only.
+func NewJar(path string) (*Jar, error) {
+	jar := &Jar{path: path, cookies: map[cookieKey]Cookie{}}
+	if path == "" {
+		return jar, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return jar, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var cookies []Cookie
+	if err := json.Unmarshal(data, &cookies); err != nil {
+		return nil, fmt.Errorf("invalid cookie jar %s: %w", path, err)
+	}
+	for _, cookie := range cookies {
+		jar.cookies[cookie.key()] = cookie
+	}
+	return jar, nil
+}
+
+// SetCookies implements http.CookieJar. Errors saving the jar are ignored
+// as the interface has no way to report them; the cookies are still kept
+// in memory.
+func (j *Jar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	now := time.Now()
+	host := canonicalHost(u.Host)
+	for _, c := range cookies {
+		cookie := Cookie{
+			Name:     c.Name,
+			Value:    c.Value,
+			Domain:   strings.ToLower(strings.TrimPrefix(c.Domain, ".")),
+			Path:     c.Path,
+			Secure:   c.Secure,
+			HttpOnly: c.HttpOnly,
+		}
+		switch {
+		case cookie.Domain == "" || cookie.Domain == host && !strings.Contains(host, "."):
+			cookie.Domain = host
+			cookie.HostOnly = true
+		case !strings.Contains(cookie.Domain, "."):
+			// a top-level domain like `com` would send the cookie to every
+			// host under it
+			continue
+		case !domainMatch(host, cookie.Domain):
+			continue
+		}
+		if cookie.Path == "" || !strings.HasPrefix(cookie.Path, "/") {
+			cookie.Path = defaultPath(requestPath(u))
+		}
+		switch {
+		case c.MaxAge < 0:
+			cookie.Expires = now
+		case c.MaxAge > 0:
+			cookie.Expires = now.Add(time.Duration(c.MaxAge) * time.Second)
+		case !c.Expires.IsZero():
+			cookie.Expires = c.Expires
+		}
+		if cookie.expired(now) {
+			delete(j.cookies, cookie.key())
+			continue
+		}
+		j.cookies[cookie.key()] = cookie
+	}
+	j.save()
+}
+
+// Cookies implements http.CookieJar.
+func (j *Jar) Cookies(u *url.URL) []*http.Cookie {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	now := time.Now()
+	var cookies []*http.Cookie
+	for _, cookie := range j.sorted() {
+		if cookie.expired(now) || !cookie.matches(u) {
+			continue
+		}
+		cookies = append(cookies, &http.Cookie{Name: cookie.Name, Value: cookie.Value})
+	}
+	return cookies
+}
+
+// All returns every unexpired cookie sorted by domain, path and name.
+func (j *Jar) All() []Cookie {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	now := time.Now()
+	var cookies []Cookie
+	for _, cookie := range j.sorted() {
+		if !cookie.expired(now) {
+			cookies = append(cookies, cookie)
+		}
+	}
+	return cookies
+}
+
+// Delete removes the cookie from the jar.
+func (j *Jar) Delete(cookie Cookie) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	delete(j.cookies, cookie.key())
+	return j.save()
+}
+
+// Replace replaces old with the cookie parsed from the Set-Cookie formatted
+// text. The domain of old is kept unless the text sets one.
+func (j *Jar) Replace(old Cookie, text string) error {
+	parsed := (&http.Response{Header: http.Header{"Set-Cookie": {text}}}).Cookies()
+	if len(parsed) != 1 {
+		return fmt.Errorf("invalid cookie %q", text)
+	}
+	c := parsed[0]
+	cookie := Cookie{
+		Name:     c.Name,
+		Value:    c.Value,
+		Domain:   strings.ToLower(strings.TrimPrefix(c.Domain, ".")),
+		Path:     c.Path,
+		Expires:  c.Expires,
+		Secure:   c.Secure,
+		HttpOnly: c.HttpOnly,
+	}
+	if cookie.Domain == "" {
+		cookie.Domain = old.Domain
+		cookie.HostOnly = true
+	}
+	if cookie.Path == "" {
+		cookie.Path = "/"
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	delete(j.cookies, old.key())
+	j.cookies[cookie.key()] = cookie
+	return j.save()
+}
+
+func (j *Jar) sorted() []Cookie {
+	cookies := make([]Cookie, 0, len(j.cookies))
+	for _, cookie := range j.cookies {
+		cookies = append(cookies, cookie)
+	}
+	sort.Slice(cookies, func(a, b int) bool {
+		if cookies[a].Domain != cookies[b].Domain {
+			return cookies[a].Domain < cookies[b].Domain
+		}
+		if cookies[a].Path != cookies[b].Path {
+			// more specific paths are sent first
+			return len(cookies[a].Path) > len(cookies[b].Path)
+		}
+		return cookies[a].Name < cookies[b].Name
+	})
+	return cookies
+}
+
+func (j *Jar) save() error {
+	if j.path == "" {
+		return nil
+	}
+	var cookies []Cookie
+	now := time.Now()
+	for _, cookie := range j.sorted() {
+		if !cookie.expired(now) {
+			cookies = append(cookies, cookie)
+		}
+	}
+	data, err := json.MarshalIndent(cookies, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(j.path, data, 0o600)
+}
+
+func canonicalHost(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.ToLower(host)
+}
+
+func domainMatch(host, domain string) bool {
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
+
+func requestPath(u *url.URL) string {
+	if u.Path == "" {
+		return "/"
+	}
+	return u.Path
+}
+
+func defaultPath(path string) string {
+	i := strings.LastIndex(path, "/")
+	if i <= 0 {
+		return "/"
+	}
+	return path[:i]
+}
+
+func pathMatch(path, cookiePath string) bool {
+	if path == cookiePath {
+		return true
+	}
+	if !strings.HasPrefix(path, cookiePath) {
+		return false
+	}
+	return strings.HasSuffix(cookiePath, "/") || path[len(cookiePath)] == '/'
+}
@@ -0,0 +1,121 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/dop251/goja"
+	"github.com/go-rq/req/internal/httpfile"
+	"github.com/go-rq/rq"
+)
+
+type pollFuncContextKey struct{}
+
+// Poll is a response received while polling a request declared with
+// `# @poll`, and whether the until condition held for it.
+type Poll struct {
+	Number int
+	Result *Result
+	Done   bool
+}
+
+func (p Poll) String() string {
+	var duration time.Duration
+	if len(p.Result.Attempts) > 0 {
+		duration = p.Result.Attempts[len(p.Result.Attempts)-1].Duration.Round(time.Millisecond)
+	}
+	return fmt.Sprintf("poll #%d: %s in %s, condition %t", p.Number, p.Result.Response.Status, duration, p.Done)
+}
+
+// PollFunc is called with every response received while polling.
+type PollFunc func(Poll)
+
+// WithPollFunc returns a new context in which fn is called with every
+// response received while polling a request.
+func WithPollFunc(ctx context.Context, fn PollFunc) context.Context {
+	return context.WithValue(ctx, pollFuncContextKey{}, fn)
+}
+
+func getPollFunc(ctx context.Context) PollFunc {
+	if fn, ok := ctx.Value(pollFuncContextKey{}).(PollFunc); ok {
+		return fn
+	}
+	return func(Poll) {}
+}
+
+// poll sends the request until the until condition of the poll is true for
+// its response, waiting the poll interval between sends, and fails once the
+// next send would start after the timeout. Only the last response is saved
+// if the request redirects its body to a file.
+func (c *Client) poll(ctx context.Context, request *httpfile.Request, poll httpfile.Poll) (*Result, error) {
+	deadline := time.Now().Add(poll.Timeout)
+	polled := *request
+	polled.Output = httpfile.Output{}
+	var polls []Poll
+	for number := 1; ; number++ {
+		result, err := c.send(ctx, &polled)
+		request.Logs, request.PreRequestAssertions = polled.Logs, polled.PreRequestAssertions
+		if err != nil {
+			result.Polls = polls
+			return result, err
+		}
+		done, err := evaluate(poll.Until, result.Response, rq.GetEnvironment(ctx))
+		polls = append(polls, Poll{Number: number, Result: result, Done: done})
+		getPollFunc(ctx)(polls[len(polls)-1])
+		result.Polls = polls
+		if err != nil {
+			return result, fmt.Errorf("evaluating @poll condition %s: %w", poll.Until, err)
+		}
+		if done {
+			c.writeOutput(ctx, request, result)
+			return result, nil
+		}
+		if time.Now().Add(poll.Every).After(deadline) {
+			return result, fmt.Errorf("polling timed out after %s: %s was still false after %d responses, the last %s",
+				poll.Timeout, poll.Until, number, result.Response.Status)
+		}
+		if err := sleep(ctx, poll.Every); err != nil {
+			return result, err
+		}
+	}
+}
+
+// evaluate runs the until condition of a poll against the response. Like in
+// response scripts, the condition sees the response as `response`, but its
+// body is parsed if it is JSON so fields can be compared directly, e.g.
+// `response.body.status === 'done'`.
+func evaluate(condition string, resp *rq.Response, env map[string]string) (bool, error) {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return false, err
+	}
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	var (
+		parsed any = string(body)
+		data   any
+	)
+	if json.Unmarshal(body, &data) == nil {
+		parsed = data
+	}
+	vm := goja.New()
+	vm.Set("response", map[string]any{
+		"body":       parsed,
+		"json":       data,
+		"headers":    resp.Header,
+		"status":     resp.Status,
+		"statusCode": resp.StatusCode,
+	})
+	vm.Set("getEnv", func(key string) string {
+		return env[key]
+	})
+	value, err := vm.RunString(condition)
+	if err != nil {
+		return false, err
+	}
+	return value.ToBoolean(), nil
+}
@@ -0,0 +1,47 @@
+package client
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-rq/rq"
+)
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		condition string
+		want      bool
+		wantErr   bool
+	}{
+		{"JSON field", `{"status": "done"}`, `response.body.status === 'done'`, true, false},
+		{"JSON field not yet set", `{"status": "pending"}`, `response.body.status === 'done'`, false, false},
+		{"JSON as json", `{"items": [1, 2]}`, `response.json.items.length === 2`, true, false},
+		{"text body", `done`, `response.body === 'done'`, true, false},
+		{"text body has no json", `done`, `response.json === null`, true, false},
+		{"status code", `{}`, `response.statusCode === 202`, true, false},
+		{"environment", `{"id": "42"}`, `response.body.id === getEnv('id')`, true, false},
+		{"truthy value", `{"count": 3}`, `response.body.count`, true, false},
+		{"syntax error", `{}`, `response.body ===`, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &rq.Response{Response: &http.Response{StatusCode: 202, Body: io.NopCloser(strings.NewReader(tt.body))}}
+			got, err := evaluate(tt.condition, resp, map[string]string{"id": "42"})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("evaluate(%q) error = %v, want error %t", tt.condition, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("evaluate(%q) = %t, want %t", tt.condition, got, tt.want)
+			}
+			// the body is left for the scripts and the response view
+			body, _ := io.ReadAll(resp.Body)
+			if string(body) != tt.body {
+				t.Errorf("body after evaluate = %q, want %q", body, tt.body)
+			}
+		})
+	}
+}
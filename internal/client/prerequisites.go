@@ -0,0 +1,169 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-rq/req/internal/httpfile"
+)
+
+// Prerequisite is a request declared with `# @depends-on` that was sent, or
+// whose cached result was reused, before the request depending on it.
+type Prerequisite struct {
+	Request httpfile.Request
+	Result  *Result
+	Err     error
+
+	// Cached is set if the result was reused from an earlier send, which
+	// happened at SentAt.
+	Cached bool
+	SentAt time.Time
+}
+
+func (p Prerequisite) String() string {
+	switch {
+	case p.Err != nil:
+		return fmt.Sprintf("prerequisite %s failed: %s", p.Request.DisplayName(), p.Err)
+	case p.Cached:
+		return fmt.Sprintf("prerequisite %s: %s (cached from %s ago)", p.Request.DisplayName(), p.Result.Response.Status, time.Since(p.SentAt).Round(time.Second))
+	}
+	var duration time.Duration
+	if len(p.Result.Attempts) > 0 {
+		duration = p.Result.Attempts[len(p.Result.Attempts)-1].Duration.Round(time.Millisecond)
+	}
+	return fmt.Sprintf("prerequisite %s: %s in %s", p.Request.DisplayName(), p.Result.Response.Status, duration)
+}
+
+// sendPrerequisites sends the requests the request depends on, transitively
+// and in dependency order, reusing results cached within the dependency TTL.
+// It stops at the first prerequisite that fails.
+func (c *Client) sendPrerequisites(ctx context.Context, request *httpfile.Request) ([]Prerequisite, error) {
+	if len(request.DependsOn()) == 0 {
+		return nil, nil
+	}
+	if request.File == "" {
+		return nil, fmt.Errorf("%s depends on other requests but is not in a file", request.DisplayName())
+	}
+	requests, err := httpfile.ParseFile(request.File)
+	if err != nil {
+		return nil, err
+	}
+	order, err := dependencyOrder(*request, requests)
+	if err != nil {
+		return nil, err
+	}
+	var prerequisites []Prerequisite
+	for _, dependency := range order {
+		if result, sentAt, ok := c.resultCache(ctx).get(dependency, c.Settings.DependencyTTL); ok {
+			prerequisites = append(prerequisites, Prerequisite{Request: dependency, Result: result, Cached: true, SentAt: sentAt})
+			continue
+		}
+		result, err := c.send(ctx, &dependency)
+		prerequisites = append(prerequisites, Prerequisite{Request: dependency, Result: result, Err: err, SentAt: time.Now()})
+		if err != nil {
+			return prerequisites, fmt.Errorf("prerequisite %s failed: %w", dependency.DisplayName(), err)
+		}
+	}
+	return prerequisites, nil
+}
+
+// dependencyOrder returns the prerequisites of the request among the requests
+// of its file, each after its own prerequisites.
+func dependencyOrder(request httpfile.Request, requests []httpfile.Request) ([]httpfile.Request, error) {
+	byName := map[string]httpfile.Request{}
+	for _, r := range requests {
+		byName[r.DisplayName()] = r
+	}
+	var (
+		order []httpfile.Request
+		done  = map[string]bool{}
+		visit func(r httpfile.Request, path []string) error
+	)
+	visit = func(r httpfile.Request, path []string) error {
+		path = append(path, r.DisplayName())
+		for _, name := range r.DependsOn() {
+			if slices.Contains(path, name) {
+				return fmt.Errorf("dependency cycle: %s -> %s", strings.Join(path, " -> "), name)
+			}
+			if done[name] {
+				continue
+			}
+			dependency, ok := byName[name]
+			if !ok {
+				return fmt.Errorf("%s depends on %q, which is not in %s", r.DisplayName(), name, request.File)
+			}
+			if err := visit(dependency, path); err != nil {
+				return err
+			}
+			done[name] = true
+			order = append(order, dependency)
+		}
+		return nil
+	}
+	if err := visit(request, nil); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+type resultCacheContextKey struct{}
+
+// WithResultCache returns a new context with an empty cache of results,
+// used for prerequisites instead of the cache of the client. Sends whose
+// environments must not share prerequisites, as their scripts set variables
+// in the environment they run in, each use their own cache.
+func WithResultCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, resultCacheContextKey{}, newResultCache())
+}
+
+// resultCache returns the cache of the context, or of the client if there is
+// none.
+func (c *Client) resultCache(ctx context.Context) *resultCache {
+	if cache, ok := ctx.Value(resultCacheContextKey{}).(*resultCache); ok {
+		return cache
+	}
+	return c.cache
+}
+
+// resultCache holds the latest successful result of every request sent.
+type resultCache struct {
+	mu      sync.Mutex
+	results map[string]cachedResult
+}
+
+type cachedResult struct {
+	result *Result
+	sentAt time.Time
+}
+
+func newResultCache() *resultCache {
+	return &resultCache{results: map[string]cachedResult{}}
+}
+
+func cacheKey(request httpfile.Request) string {
+	return request.File + "#" + request.DisplayName()
+}
+
+func (c *resultCache) put(request *httpfile.Request, result *Result) {
+	if request.File == "" {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.results[cacheKey(*request)] = cachedResult{result: result, sentAt: time.Now()}
+}
+
+// get returns the result of the request if it was sent less than ttl ago.
+func (c *resultCache) get(request httpfile.Request, ttl time.Duration) (*Result, time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cached, ok := c.results[cacheKey(request)]
+	if !ok || time.Since(cached.sentAt) >= ttl {
+		return nil, time.Time{}, false
+	}
+	return cached.result, cached.sentAt, true
+}
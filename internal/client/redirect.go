@@ -0,0 +1,22 @@
+package client
+
+import "net/http"
+
+// Redirect is a single hop in a redirect chain.
+type Redirect struct {
+	Method     string
+	URL        string
+	Status     string
+	StatusCode int
+	Location   string
+}
+
+func newRedirect(prev, next *http.Request) Redirect {
+	return Redirect{
+		Method:     prev.Method,
+		URL:        prev.URL.String(),
+		Status:     next.Response.Status,
+		StatusCode: next.Response.StatusCode,
+		Location:   next.Response.Header.Get("Location"),
+	}
+}
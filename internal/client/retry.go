@@ -0,0 +1,193 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"strings"
+	"time"
+
+	"github.com/go-rq/req/internal/httpfile"
+)
+
+// RetryCondition is a kind of failure that can be retried.
+type RetryCondition string
+
+const (
+	// RetryOn5xx retries responses with a 5xx status code.
+	RetryOn5xx RetryCondition = "5xx"
+	// RetryOnConnect retries attempts that failed to connect to the server.
+	RetryOnConnect RetryCondition = "connect"
+	// RetryOnTimeout retries attempts that exceeded the timeout.
+	RetryOnTimeout RetryCondition = "timeout"
+)
+
+// RetryConditions implements flag.Value for a comma separated list of
+// retry conditions.
+type RetryConditions []RetryCondition
+
+func (r RetryConditions) String() string {
+	parts := make([]string, len(r))
+	for i, condition := range r {
+		parts[i] = string(condition)
+	}
+	return strings.Join(parts, ",")
+}
+
+func (r *RetryConditions) Set(value string) error {
+	var conditions RetryConditions
+	for _, part := range strings.Split(value, ",") {
+		switch condition := RetryCondition(strings.TrimSpace(part)); condition {
+		case RetryOn5xx, RetryOnConnect, RetryOnTimeout:
+			conditions = append(conditions, condition)
+		case "":
+		default:
+			return fmt.Errorf("unknown retry condition %q", part)
+		}
+	}
+	*r = conditions
+	return nil
+}
+
+func (r RetryConditions) has(condition RetryCondition) bool {
+	for _, c := range r {
+		if c == condition {
+			return true
+		}
+	}
+	return false
+}
+
+// Attempt records a single try at sending a request.
+type Attempt struct {
+	Number     int
+	Status     string
+	StatusCode int
+	Duration   time.Duration
+	Err        error
+
+	// Redirects holds the redirects followed during the attempt, in order.
+	Redirects []Redirect
+
+	Timing Timing
+}
+
+func (a Attempt) String() string {
+	if a.Err != nil {
+		return fmt.Sprintf("#%d failed after %s: %s", a.Number, a.Duration, a.Err)
+	}
+	return fmt.Sprintf("#%d %s in %s", a.Number, a.Status, a.Duration)
+}
+
+// runner implements rq.RequestRunner, retrying failed attempts according to
+// the settings and recording each of them.
+type runner struct {
+	client     *http.Client
+	settings   Settings
+	noRedirect bool
+	request    *httpfile.Request
+	attempts   []Attempt
+}
+
+func (r *runner) Do(req *http.Request) (*http.Response, error) {
+	if err := encodeBody(req, r.request); err != nil {
+		return nil, err
+	}
+	backoff := r.settings.Backoff
+	for number := 1; ; number++ {
+		if number > 1 {
+			if err := sleep(req.Context(), backoff); err != nil {
+				return nil, err
+			}
+			backoff *= 2
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, err
+				}
+				req.Body = body
+			}
+		}
+		tracer := newTracer()
+		resp, redirects, err := r.attempt(req, tracer)
+		timing := tracer.timing(time.Now())
+		attempt := Attempt{Number: number, Duration: timing.Total, Err: err, Redirects: redirects, Timing: timing}
+		if resp != nil {
+			attempt.Status = resp.Status
+			attempt.StatusCode = resp.StatusCode
+		}
+		r.attempts = append(r.attempts, attempt)
+		if number > r.settings.Retries || !r.retryable(req.Context(), resp, err) {
+			return resp, err
+		}
+	}
+}
+
+// attempt sends the request once. The body is read before returning so the
+// timeout covers the whole transfer.
+func (r *runner) attempt(req *http.Request, tracer *tracer) (*http.Response, []Redirect, error) {
+	ctx := httptrace.WithClientTrace(req.Context(), tracer.trace())
+	if r.settings.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.settings.Timeout)
+		defer cancel()
+	}
+	var redirects []Redirect
+	client := *r.client
+	client.CheckRedirect = func(next *http.Request, via []*http.Request) error {
+		if r.noRedirect {
+			return http.ErrUseLastResponse
+		}
+		redirects = append(redirects, newRedirect(via[len(via)-1], next))
+		if len(via) > r.settings.MaxRedirects {
+			return fmt.Errorf("stopped after %d redirects", r.settings.MaxRedirects)
+		}
+		return nil
+	}
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, redirects, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, redirects, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, redirects, nil
+}
+
+func (r *runner) retryable(ctx context.Context, resp *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		// the send itself was cancelled
+		return false
+	}
+	if err == nil {
+		return resp.StatusCode >= 500 && r.settings.RetryOn.has(RetryOn5xx)
+	}
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return r.settings.RetryOn.has(RetryOnTimeout)
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return r.settings.RetryOn.has(RetryOnConnect)
+	}
+	return false
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,119 @@
+package client
+
+import (
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// Phase is a step of sending a request, relative to the start of the attempt.
+type Phase struct {
+	Name     string
+	Start    time.Duration
+	Duration time.Duration
+}
+
+// Timing breaks down where the time of an attempt was spent. When redirects
+// are followed the phases describe the last request of the chain.
+type Timing struct {
+	Phases []Phase
+	Total  time.Duration
+
+	// ConnectionReused is set when an idle connection was reused, in which
+	// case there are no DNS, connect or TLS phases.
+	ConnectionReused bool
+}
+
+// Phase returns the duration of the named phase.
+func (t Timing) Phase(name string) time.Duration {
+	for _, phase := range t.Phases {
+		if phase.Name == name {
+			return phase.Duration
+		}
+	}
+	return 0
+}
+
+const (
+	PhaseDNS      = "DNS Lookup"
+	PhaseConnect  = "TCP Connect"
+	PhaseTLS      = "TLS Handshake"
+	PhaseTTFB     = "Time to First Byte"
+	PhaseTransfer = "Content Transfer"
+)
+
+// tracer records the timestamps reported by httptrace during an attempt.
+type tracer struct {
+	mu           sync.Mutex
+	start        time.Time
+	dnsStart     time.Time
+	dnsDone      time.Time
+	connectStart time.Time
+	connectDone  time.Time
+	tlsStart     time.Time
+	tlsDone      time.Time
+	wroteRequest time.Time
+	firstByte    time.Time
+	reused       bool
+}
+
+func newTracer() *tracer {
+	return &tracer{start: time.Now()}
+}
+
+func (t *tracer) record(field *time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	*field = time.Now()
+}
+
+func (t *tracer) trace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		GetConn: func(string) {
+			// a new request of a redirect chain starts, only the last one is kept
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.dnsStart, t.dnsDone = time.Time{}, time.Time{}
+			t.connectStart, t.connectDone = time.Time{}, time.Time{}
+			t.tlsStart, t.tlsDone = time.Time{}, time.Time{}
+			t.wroteRequest, t.firstByte = time.Time{}, time.Time{}
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.reused = info.Reused
+		},
+		DNSStart:             func(httptrace.DNSStartInfo) { t.record(&t.dnsStart) },
+		DNSDone:              func(httptrace.DNSDoneInfo) { t.record(&t.dnsDone) },
+		ConnectStart:         func(string, string) { t.record(&t.connectStart) },
+		ConnectDone:          func(string, string, error) { t.record(&t.connectDone) },
+		TLSHandshakeStart:    func() { t.record(&t.tlsStart) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { t.record(&t.tlsDone) },
+		WroteRequest:         func(httptrace.WroteRequestInfo) { t.record(&t.wroteRequest) },
+		GotFirstResponseByte: func() { t.record(&t.firstByte) },
+	}
+}
+
+// timing returns the phases recorded up to end, when the body was read.
+func (t *tracer) timing(end time.Time) Timing {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	timing := Timing{Total: end.Sub(t.start), ConnectionReused: t.reused}
+	add := func(name string, start, done time.Time) {
+		if start.IsZero() || done.IsZero() {
+			return
+		}
+		timing.Phases = append(timing.Phases, Phase{
+			Name:     name,
+			Start:    start.Sub(t.start),
+			Duration: done.Sub(start),
+		})
+	}
+	add(PhaseDNS, t.dnsStart, t.dnsDone)
+	add(PhaseConnect, t.connectStart, t.connectDone)
+	add(PhaseTLS, t.tlsStart, t.tlsDone)
+	add(PhaseTTFB, t.wroteRequest, t.firstByte)
+	add(PhaseTransfer, t.firstByte, end)
+	return timing
+}
@@ -0,0 +1,110 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// TransportSettings configure the connections made to servers. Unlike
+// Settings they are fixed for the lifetime of a client.
+type TransportSettings struct {
+	// Proxy is the URL of the HTTP(S) proxy requests are routed through.
+	// When empty the proxy is taken from the HTTP_PROXY, HTTPS_PROXY and
+	// NO_PROXY environment variables.
+	Proxy string
+
+	// CACert is a PEM file with certificates trusted in addition to the
+	// system pool.
+	CACert string
+
+	// Cert and Key are PEM files with the client certificate and private
+	// key presented to servers requiring mutual TLS.
+	Cert string
+	Key  string
+
+	// Insecure disables verification of server certificates.
+	Insecure bool
+
+	// TLSMinVersion is the minimum TLS version accepted: 1.0, 1.1, 1.2 or 1.3.
+	TLSMinVersion string
+
+	// ServerName overrides the server name sent with SNI and used to verify
+	// the server certificate.
+	ServerName string
+
+	// MaxIdleConnsPerHost is the number of connections kept open to each
+	// server for reuse, e.g. one per worker of a benchmark. Zero keeps the
+	// default of net/http.
+	MaxIdleConnsPerHost int
+}
+
+func (t TransportSettings) transport() (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if t.Proxy != "" {
+		proxy, err := url.Parse(t.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+	if t.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = t.MaxIdleConnsPerHost
+		transport.MaxIdleConns = max(transport.MaxIdleConns, t.MaxIdleConnsPerHost)
+	}
+	config, err := t.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	transport.TLSClientConfig = config
+	return transport, nil
+}
+
+func (t TransportSettings) tlsConfig() (*tls.Config, error) {
+	config := &tls.Config{
+		InsecureSkipVerify: t.Insecure,
+		ServerName:         t.ServerName,
+	}
+	if t.TLSMinVersion != "" {
+		version, ok := tlsVersions[t.TLSMinVersion]
+		if !ok {
+			return nil, fmt.Errorf("unsupported TLS version %q", t.TLSMinVersion)
+		}
+		config.MinVersion = version
+	}
+	if t.CACert != "" {
+		pem, err := os.ReadFile(t.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read CA certificate: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", t.CACert)
+		}
+		config.RootCAs = pool
+	}
+	if t.Cert != "" || t.Key != "" {
+		if t.Cert == "" || t.Key == "" {
+			return nil, fmt.Errorf("a client certificate requires both a cert and a key")
+		}
+		cert, err := tls.LoadX509KeyPair(t.Cert, t.Key)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return config, nil
+}
@@ -0,0 +1,75 @@
+package httpfile
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// BodyFile is a `< path` line in the body of a request, which is replaced by
+// the contents of the file when the request is sent. With `<@ path` the
+// variables referenced in the file are expanded as well.
+type BodyFile struct {
+	Path   string
+	Expand bool
+}
+
+func (f BodyFile) String() string {
+	if f.Expand {
+		return "<@ " + f.Path
+	}
+	return "< " + f.Path
+}
+
+// bodyFileMarker stands in for the ith body file in the body handed to rq. It
+// holds no variable reference so rq leaves it alone when applying the
+// environment.
+func bodyFileMarker(i int) string {
+	return fmt.Sprintf("\x00req-body-file-%d\x00", i)
+}
+
+// InlineBody replaces the body file markers in body with the contents of the
+// files, resolved against the directory of the .http file. A marker takes the
+// line break following it along so the contents are sent as they are.
+func (r Request) InlineBody(body []byte, env map[string]string) ([]byte, error) {
+	for i, file := range r.BodyFiles {
+		marker := []byte(bodyFileMarker(i))
+		if !bytes.Contains(body, marker) {
+			continue
+		}
+		data, err := r.readFile(file.Path)
+		if err != nil {
+			return nil, fmt.Errorf("reading request body: %w", err)
+		}
+		if file.Expand {
+			data = []byte(Expand(string(data), env))
+		}
+		body = bytes.Replace(body, append(marker, '\n'), data, 1)
+		body = bytes.Replace(body, marker, data, 1)
+	}
+	return body, nil
+}
+
+// readFile reads a file referenced by the request, resolving relative paths
+// against the directory of the .http file.
+func (r Request) readFile(path string) ([]byte, error) {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(r.Dir, path)
+	}
+	return os.ReadFile(path)
+}
+
+// HttpText returns the request in .http syntax with the body file references
+// in place of their markers.
+func (r Request) HttpText() string {
+	return r.restoreBodyFiles(r.Request.HttpText())
+}
+
+func (r Request) restoreBodyFiles(text string) string {
+	for i, file := range r.BodyFiles {
+		text = strings.Replace(text, bodyFileMarker(i), file.String(), 1)
+	}
+	return text
+}
@@ -0,0 +1,394 @@
+// Package httpfile parses .http files into requests. Parsing of the request
+// itself is delegated to rq; this package adds support for the comment
+// directives req understands, e.g.
+//
+//	### Get User
+//	# @timeout 5s
+//	# @retry 3
+//	GET {{host}}/users/1
+//
+// as well as `>> path` and `>>! path` lines saving the response body to a
+// file, and `< path` body lines reading the body from a file.
+package httpfile
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-rq/rq"
+)
+
+var (
+	directiveRegexp   = regexp.MustCompile(`^(?:#|//)\s*@([\w-]+)\s*(.*)$`)
+	scriptFileRegexp  = regexp.MustCompile(`^<\s*(.*\.js)\s*$`)
+	outputRegexp      = regexp.MustCompile(`^>>(!)?\s*(.*)$`)
+	bodyFileRegexp    = regexp.MustCompile(`^<(@)?\s+([^\s{].*)$`)
+	scriptStartRegexp = regexp.MustCompile(`^[<>]\s*\{%`)
+	variableRegexp    = regexp.MustCompile(`{{(.*?)}}`)
+)
+
+// validators checks the values of known directives when a file is parsed so
+// that mistakes are reported up front rather than when the request is sent.
+// Unknown directives are kept but not validated.
+var validators = map[string]func(string) error{
+	"timeout":       validateDuration,
+	"retry":         validateCount,
+	"no-redirect":   validateFlag,
+	"no-cookie-jar": validateFlag,
+	"depends-on":    validateName,
+	"data":          validatePath,
+	"tag":           validateTags,
+	"poll":          validatePoll,
+}
+
+// Directive is a `# @name value` comment attached to a request.
+type Directive struct {
+	Name  string
+	Value string
+}
+
+// Request is an rq.Request along with the directives declared for it.
+type Request struct {
+	rq.Request
+
+	// File is the path of the .http file the request was parsed from, if any.
+	File string
+
+	// Dir is the directory of the file the request was parsed from. Relative
+	// paths referenced by the request are resolved against it.
+	Dir string
+
+	Directives []Directive
+
+	// Output is the file the response body is saved to, if any.
+	Output Output
+
+	// BodyFiles are the files referenced by `< path` lines in the body, which
+	// is holding a marker in their place until the request is sent.
+	BodyFiles []BodyFile
+}
+
+// Output is a `>> path` line redirecting the response body to a file. The
+// file is overwritten if declared with `>>! path`, otherwise a numeric suffix
+// is added to the name of an existing file.
+type Output struct {
+	Path      string
+	Overwrite bool
+}
+
+// OutputPath returns the path the response body is saved to with the
+// variables expanded and resolved against the directory of the .http file.
+func (r Request) OutputPath(env map[string]string) (string, bool) {
+	if r.Output.Path == "" {
+		return "", false
+	}
+	path := Expand(r.Output.Path, env)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(r.Dir, path)
+	}
+	return path, true
+}
+
+// Directive returns the value of the last directive with the given name.
+func (r Request) Directive(name string) (string, bool) {
+	for i := len(r.Directives) - 1; i >= 0; i-- {
+		if r.Directives[i].Name == name {
+			return r.Directives[i].Value, true
+		}
+	}
+	return "", false
+}
+
+// Timeout returns the per attempt timeout set with `# @timeout <duration>`.
+func (r Request) Timeout() (time.Duration, bool) {
+	value, ok := r.Directive("timeout")
+	if !ok {
+		return 0, false
+	}
+	timeout, err := time.ParseDuration(value)
+	return timeout, err == nil
+}
+
+// Retries returns the number of retries set with `# @retry <count>`.
+func (r Request) Retries() (int, bool) {
+	value, ok := r.Directive("retry")
+	if !ok {
+		return 0, false
+	}
+	retries, err := strconv.Atoi(value)
+	return retries, err == nil
+}
+
+// NoRedirect reports whether redirects are disabled with `# @no-redirect`.
+func (r Request) NoRedirect() bool {
+	_, ok := r.Directive("no-redirect")
+	return ok
+}
+
+// NoCookieJar reports whether the request opts out of the session cookie jar
+// with `# @no-cookie-jar`.
+func (r Request) NoCookieJar() bool {
+	_, ok := r.Directive("no-cookie-jar")
+	return ok
+}
+
+// DependsOn returns the names of the requests declared as prerequisites with
+// `# @depends-on <name>`, which may be repeated or list several names
+// separated by commas.
+func (r Request) DependsOn() []string {
+	return r.list("depends-on")
+}
+
+// Tags returns the tags declared with `# @tag <tag>`, which may be repeated
+// or list several tags separated by commas.
+func (r Request) Tags() []string {
+	return r.list("tag")
+}
+
+// HasTag reports whether the request is tagged with tag, ignoring case.
+func (r Request) HasTag(tag string) bool {
+	for _, t := range r.Tags() {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// list returns the comma separated values of every directive with the
+// given name.
+func (r Request) list(name string) []string {
+	var values []string
+	for _, directive := range r.Directives {
+		if directive.Name != name {
+			continue
+		}
+		for _, value := range strings.Split(directive.Value, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
+
+// DataFile returns the path of the data set declared with `# @data <path>`,
+// resolved against the directory of the .http file.
+func (r Request) DataFile() (string, bool) {
+	path, ok := r.Directive("data")
+	if !ok {
+		return "", false
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(r.Dir, path)
+	}
+	return path, true
+}
+
+// String returns the request in .http syntax including its directives.
+func (r Request) String() string {
+	var directives strings.Builder
+	for _, directive := range r.Directives {
+		if directive.Value == "" {
+			fmt.Fprintf(&directives, "# @%s\n", directive.Name)
+			continue
+		}
+		fmt.Fprintf(&directives, "# @%s %s\n", directive.Name, directive.Value)
+	}
+	text := r.restoreBodyFiles(r.Request.String())
+	if r.Output.Path != "" {
+		operator := ">>"
+		if r.Output.Overwrite {
+			operator = ">>!"
+		}
+		text = strings.TrimRight(text, "\n") + fmt.Sprintf("\n\n%s %s\n", operator, r.Output.Path)
+	}
+	if r.Name == "" {
+		return directives.String() + text
+	}
+	name, rest, _ := strings.Cut(text, "\n")
+	return name + "\n" + directives.String() + rest
+}
+
+// Expand replaces the {{name}} references to variables defined in env,
+// leaving unknown references untouched like rq does.
+func Expand(text string, env map[string]string) string {
+	return variableRegexp.ReplaceAllStringFunc(text, func(match string) string {
+		if value, ok := env[match[2:len(match)-2]]; ok {
+			return value
+		}
+		return match
+	})
+}
+
+// ParseFile parses all requests in the .http file at path.
+func ParseFile(path string) ([]Request, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	requests, err := Parse(filepath.Dir(path), string(data))
+	for i := range requests {
+		requests[i].File = path
+	}
+	return requests, err
+}
+
+// Parse parses all requests in input. Relative paths are resolved against dir.
+func Parse(dir, input string) ([]Request, error) {
+	var requests []Request
+	for _, chunk := range splitRequests(input) {
+		if strings.TrimSpace(chunk.text) == "" {
+			continue
+		}
+		text, request, err := preprocess(dir, chunk)
+		if err != nil {
+			return nil, err
+		}
+		reqs, err := rq.ParseRequests(text)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", chunk.line, err)
+		}
+		for _, req := range reqs {
+			request.Request = req
+			requests = append(requests, request)
+		}
+	}
+	return requests, nil
+}
+
+type chunk struct {
+	text string
+	line int
+}
+
+// splitRequests splits the input at the request separators so that
+// directives can be associated with the request they are declared in.
+func splitRequests(input string) []chunk {
+	var (
+		chunks  []chunk
+		builder strings.Builder
+		start   = 1
+	)
+	scanner := bufio.NewScanner(strings.NewReader(input))
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if strings.HasPrefix(text, rq.RequestSeparator) && builder.Len() > 0 {
+			chunks = append(chunks, chunk{text: builder.String(), line: start})
+			builder.Reset()
+			start = line
+		}
+		builder.WriteString(text + "\n")
+	}
+	if builder.Len() > 0 {
+		chunks = append(chunks, chunk{text: builder.String(), line: start})
+	}
+	return chunks
+}
+
+// preprocess removes the lines rq does not understand from the request text
+// and returns the request they describe: directive comments, the output
+// redirection and body file references, which are replaced by a marker.
+// Script file references are made absolute so rq resolves them relative to
+// the .http file rather than the working directory.
+func preprocess(dir string, c chunk) (string, Request, error) {
+	var (
+		request  = Request{Dir: dir}
+		builder  strings.Builder
+		inScript bool
+	)
+	for i, line := range strings.Split(strings.TrimSuffix(c.text, "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if inScript || scriptStartRegexp.MatchString(trimmed) {
+			inScript = !strings.Contains(trimmed, "%}")
+			builder.WriteString(line + "\n")
+			continue
+		}
+		if match := directiveRegexp.FindStringSubmatch(trimmed); match != nil {
+			directive := Directive{Name: match[1], Value: strings.TrimSpace(match[2])}
+			if validate, ok := validators[directive.Name]; ok {
+				if err := validate(directive.Value); err != nil {
+					return "", request, fmt.Errorf("line %d: invalid @%s directive: %w", c.line+i, directive.Name, err)
+				}
+			}
+			request.Directives = append(request.Directives, directive)
+			continue
+		}
+		if match := outputRegexp.FindStringSubmatch(trimmed); match != nil {
+			if match[2] == "" {
+				return "", request, fmt.Errorf("line %d: missing file after %s", c.line+i, trimmed)
+			}
+			request.Output = Output{Path: strings.TrimSpace(match[2]), Overwrite: match[1] != ""}
+			continue
+		}
+		if match := scriptFileRegexp.FindStringSubmatch(trimmed); match != nil {
+			if dir != "" && !filepath.IsAbs(match[1]) {
+				line = "< " + filepath.Join(dir, match[1])
+			}
+			builder.WriteString(line + "\n")
+			continue
+		}
+		if match := bodyFileRegexp.FindStringSubmatch(trimmed); match != nil {
+			request.BodyFiles = append(request.BodyFiles, BodyFile{Path: match[2], Expand: match[1] != ""})
+			builder.WriteString(bodyFileMarker(len(request.BodyFiles)-1) + "\n")
+			continue
+		}
+		builder.WriteString(line + "\n")
+	}
+	return builder.String(), request, nil
+}
+
+func validateDuration(value string) error {
+	_, err := time.ParseDuration(value)
+	return err
+}
+
+func validateCount(value string) error {
+	count, err := strconv.Atoi(value)
+	if err != nil {
+		return err
+	}
+	if count < 0 {
+		return fmt.Errorf("%d is negative", count)
+	}
+	return nil
+}
+
+func validateName(value string) error {
+	if strings.Trim(value, ", ") == "" {
+		return fmt.Errorf("missing request name")
+	}
+	return nil
+}
+
+func validatePoll(value string) error {
+	_, err := ParsePoll(value)
+	return err
+}
+
+func validateTags(value string) error {
+	if strings.Trim(value, ", ") == "" {
+		return fmt.Errorf("missing tag")
+	}
+	return nil
+}
+
+func validatePath(value string) error {
+	if value == "" {
+		return fmt.Errorf("missing path")
+	}
+	return nil
+}
+
+func validateFlag(value string) error {
+	if value != "" {
+		return fmt.Errorf("unexpected value %q", value)
+	}
+	return nil
+}
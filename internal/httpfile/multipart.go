@@ -0,0 +1,145 @@
+package httpfile
+
+import (
+	"bytes"
+	"errors"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var headerRegexp = regexp.MustCompile(`^([\w-]+):\s*(.*)$`)
+
+// part is a section of a multipart body as written in the .http file.
+type part struct {
+	header textproto.MIMEHeader
+	lines  []string
+}
+
+// EncodeBody returns the body to send for the request and its content type.
+// Body file markers are replaced by the contents of the files. Multipart
+// bodies are re-encoded with CRLF line breaks, file parts getting a content
+// type from the file name when they declare none. If the content type lacks
+// the boundary, it is taken from the first delimiter line of the body.
+func (r Request) EncodeBody(contentType string, body []byte, env map[string]string) ([]byte, string, error) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") {
+		body, err := r.InlineBody(body, env)
+		return body, contentType, err
+	}
+	boundary := params["boundary"]
+	if boundary == "" {
+		for _, line := range strings.Split(string(body), "\n") {
+			if line = strings.TrimSpace(line); strings.HasPrefix(line, "--") {
+				boundary = strings.TrimPrefix(line, "--")
+				break
+			}
+		}
+		if boundary == "" {
+			return nil, "", errors.New("multipart body has no boundary")
+		}
+		params["boundary"] = boundary
+		contentType = mime.FormatMediaType(mediaType, params)
+	}
+	parts := parseParts(body, boundary)
+	buf := &bytes.Buffer{}
+	writer := multipart.NewWriter(buf)
+	if err := writer.SetBoundary(boundary); err != nil {
+		return nil, "", err
+	}
+	for _, p := range parts {
+		content, err := r.partContent(p, env)
+		if err != nil {
+			return nil, "", err
+		}
+		w, err := writer.CreatePart(p.header)
+		if err != nil {
+			return nil, "", err
+		}
+		w.Write(content)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), contentType, nil
+}
+
+// partContent returns the content of the part, reading the file of a part
+// consisting of a single body file reference.
+func (r Request) partContent(p part, env map[string]string) ([]byte, error) {
+	text := strings.Join(p.lines, "\n")
+	for i, file := range r.BodyFiles {
+		if text != bodyFileMarker(i) {
+			continue
+		}
+		content, err := r.InlineBody([]byte(text), env)
+		if err != nil {
+			return nil, err
+		}
+		if p.header.Get("Content-Type") == "" {
+			contentType := mime.TypeByExtension(filepath.Ext(file.Path))
+			if contentType == "" {
+				contentType = http.DetectContentType(content)
+			}
+			p.header.Set("Content-Type", contentType)
+		}
+		return content, nil
+	}
+	return r.InlineBody([]byte(text), env)
+}
+
+// parseParts splits the body at the boundary delimiter lines. Text before
+// the first and after the closing delimiter is ignored, as are blank lines
+// ending a part. The headers of a part end at the first line that is not a
+// header since rq drops the blank lines of the body.
+func parseParts(body []byte, boundary string) []part {
+	var (
+		parts     []part
+		current   *part
+		inHeaders bool
+		delimiter = "--" + boundary
+	)
+	for _, line := range strings.Split(string(body), "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if line == delimiter || line == delimiter+"--" {
+			if current != nil {
+				parts = append(parts, *current)
+			}
+			current = nil
+			if line == delimiter+"--" {
+				break
+			}
+			current = &part{header: textproto.MIMEHeader{}}
+			inHeaders = true
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		if inHeaders {
+			if line == "" {
+				inHeaders = false
+				continue
+			}
+			if match := headerRegexp.FindStringSubmatch(line); match != nil {
+				current.header.Add(match[1], match[2])
+				continue
+			}
+			inHeaders = false
+		}
+		current.lines = append(current.lines, line)
+	}
+	if current != nil {
+		parts = append(parts, *current)
+	}
+	for i := range parts {
+		for len(parts[i].lines) > 0 && parts[i].lines[len(parts[i].lines)-1] == "" {
+			parts[i].lines = parts[i].lines[:len(parts[i].lines)-1]
+		}
+	}
+	return parts
+}
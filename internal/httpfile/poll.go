@@ -0,0 +1,78 @@
+package httpfile
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Default interval and timeout of a `# @poll` directive.
+const (
+	DefaultPollEvery   = time.Second
+	DefaultPollTimeout = time.Minute
+)
+
+// Poll is a `# @poll every=2s timeout=60s until="<condition>"` directive,
+// re-sending the request every interval until the JavaScript condition is
+// true for its response or the timeout expires.
+type Poll struct {
+	Every   time.Duration
+	Timeout time.Duration
+	Until   string
+}
+
+// Poll returns the polling declared for the request with `# @poll`.
+func (r Request) Poll() (Poll, bool) {
+	value, ok := r.Directive("poll")
+	if !ok {
+		return Poll{}, false
+	}
+	poll, err := ParsePoll(value)
+	return poll, err == nil
+}
+
+// ParsePoll parses the key=value pairs of a `# @poll` directive. Values
+// containing spaces are written in double quotes.
+func ParsePoll(value string) (Poll, error) {
+	poll := Poll{Every: DefaultPollEvery, Timeout: DefaultPollTimeout}
+	rest := strings.TrimSpace(value)
+	for rest != "" {
+		key, v, ok := strings.Cut(rest, "=")
+		if !ok || strings.ContainsAny(key, " \t") {
+			return poll, fmt.Errorf("expected key=value, got %q", rest)
+		}
+		if strings.HasPrefix(v, `"`) {
+			quoted, err := strconv.QuotedPrefix(v)
+			if err != nil {
+				return poll, fmt.Errorf("unterminated quote in %s", key)
+			}
+			rest = strings.TrimSpace(v[len(quoted):])
+			v, _ = strconv.Unquote(quoted)
+		} else {
+			v, rest, _ = strings.Cut(v, " ")
+			rest = strings.TrimSpace(rest)
+		}
+		var err error
+		switch key {
+		case "every":
+			poll.Every, err = time.ParseDuration(v)
+		case "timeout":
+			poll.Timeout, err = time.ParseDuration(v)
+		case "until":
+			poll.Until = v
+		default:
+			return poll, fmt.Errorf("unknown key %q", key)
+		}
+		if err != nil {
+			return poll, fmt.Errorf("invalid %s: %w", key, err)
+		}
+	}
+	if strings.TrimSpace(poll.Until) == "" {
+		return poll, fmt.Errorf("missing until condition")
+	}
+	if poll.Every <= 0 || poll.Timeout <= 0 {
+		return poll, fmt.Errorf("every and timeout must be positive")
+	}
+	return poll, nil
+}
@@ -0,0 +1,88 @@
+package httpfile
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParsePoll(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    Poll
+		wantErr string
+	}{
+		{
+			name:  "defaults when only until is given",
+			value: `until=done`,
+			want:  Poll{Every: DefaultPollEvery, Timeout: DefaultPollTimeout, Until: "done"},
+		},
+		{
+			name:  "quoted until with spaces",
+			value: `every=2s until="response.body.status === 'done'" timeout=30s`,
+			want:  Poll{Every: 2 * time.Second, Timeout: 30 * time.Second, Until: "response.body.status === 'done'"},
+		},
+		{
+			name:  "escaped quote",
+			value: `until="response.body.name === \"ada\""`,
+			want:  Poll{Every: DefaultPollEvery, Timeout: DefaultPollTimeout, Until: `response.body.name === "ada"`},
+		},
+		{
+			name:  "extra spaces",
+			value: `  every=500ms   until=done  `,
+			want:  Poll{Every: 500 * time.Millisecond, Timeout: DefaultPollTimeout, Until: "done"},
+		},
+		{
+			name:    "unknown key",
+			value:   `interval=2s until=done`,
+			wantErr: `unknown key "interval"`,
+		},
+		{
+			name:    "unterminated quote",
+			value:   `until="response.body.done`,
+			wantErr: "unterminated quote in until",
+		},
+		{
+			name:    "zero every",
+			value:   `every=0s until=done`,
+			wantErr: "every and timeout must be positive",
+		},
+		{
+			name:    "negative timeout",
+			value:   `timeout=-1s until=done`,
+			wantErr: "every and timeout must be positive",
+		},
+		{
+			name:    "invalid duration",
+			value:   `every=often until=done`,
+			wantErr: `invalid every: time: invalid duration "often"`,
+		},
+		{
+			name:    "missing until",
+			value:   `every=2s`,
+			wantErr: "missing until condition",
+		},
+		{
+			name:    "missing value",
+			value:   `until`,
+			wantErr: `expected key=value, got "until"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePoll(tt.value)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("ParsePoll(%q) error = %v, want %q", tt.value, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParsePoll(%q): %v", tt.value, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParsePoll(%q) = %+v, want %+v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package pretty
+
+import (
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// textMediaTypes are non text/* media types holding text.
+var textMediaTypes = []string{
+	"application/javascript",
+	"application/x-www-form-urlencoded",
+	"application/graphql",
+	"application/sql",
+}
+
+// IsBinary reports whether the body should not be displayed as text. The
+// content type decides when it is known, otherwise the body is sniffed.
+func IsBinary(contentType string, body []byte) bool {
+	if len(body) == 0 {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType == "" || mediaType == "application/octet-stream" {
+		mediaType, _, _ = mime.ParseMediaType(http.DetectContentType(body))
+		if mediaType == "application/octet-stream" {
+			return !utf8.Valid(body)
+		}
+	}
+	if strings.HasPrefix(mediaType, "text/") || isJSON(mediaType) || isXML(mediaType) || isYAML(mediaType) {
+		return false
+	}
+	for _, textMediaType := range textMediaTypes {
+		if mediaType == textMediaType {
+			return false
+		}
+	}
+	return true
+}
+
+// IsImage reports whether the content type is an image.
+func IsImage(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	return strings.HasPrefix(mediaType, "image/")
+}
+
+// HexDump returns a hex dump of the first limit bytes of the body.
+func HexDump(body []byte, limit int) string {
+	if len(body) <= limit {
+		return hex.Dump(body)
+	}
+	return hex.Dump(body[:limit]) + fmt.Sprintf("... %d more bytes\n", len(body)-limit)
+}
@@ -0,0 +1,285 @@
+// Package pretty formats response bodies for display based on their
+// content type and picks the chroma lexer to highlight them with.
+package pretty
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/alecthomas/chroma/lexers"
+)
+
+const (
+	JSONLexer  = "json"
+	XMLLexer   = "xml"
+	HTMLLexer  = "html"
+	YAMLLexer  = "yaml"
+	PlainLexer = "plaintext"
+)
+
+// Format returns the body formatted for display along with the name of the
+// lexer to highlight it with. Bodies that fail to parse as their content type
+// are returned unchanged.
+func Format(contentType string, body []byte) (string, string) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = strings.ToLower(strings.TrimSpace(contentType))
+	}
+	switch {
+	case strings.HasPrefix(mediaType, "multipart/") && params["boundary"] != "":
+		return formatMultipart(body, params["boundary"]), PlainLexer
+	case isJSON(mediaType):
+		return formatJSON(body), JSONLexer
+	case mediaType == "text/html" || mediaType == "application/xhtml+xml":
+		return formatMarkup(body, true), HTMLLexer
+	case isXML(mediaType):
+		return formatMarkup(body, false), XMLLexer
+	case mediaType == "application/x-www-form-urlencoded":
+		return formatForm(body), PlainLexer
+	case isYAML(mediaType):
+		return string(body), YAMLLexer
+	}
+	if lexer := lexers.MatchMimeType(mediaType); lexer != nil {
+		return string(body), lexer.Config().Name
+	}
+	return string(body), PlainLexer
+}
+
+func isJSON(mediaType string) bool {
+	return mediaType == "application/json" ||
+		strings.HasSuffix(mediaType, "+json") ||
+		strings.HasSuffix(mediaType, "-json") ||
+		strings.HasSuffix(mediaType, "/ndjson") ||
+		strings.HasSuffix(mediaType, "/x-ndjson")
+}
+
+func isXML(mediaType string) bool {
+	return mediaType == "application/xml" ||
+		mediaType == "text/xml" ||
+		strings.HasSuffix(mediaType, "+xml")
+}
+
+func isYAML(mediaType string) bool {
+	return mediaType == "application/yaml" ||
+		mediaType == "application/x-yaml" ||
+		mediaType == "text/yaml" ||
+		mediaType == "text/x-yaml" ||
+		strings.HasSuffix(mediaType, "+yaml")
+}
+
+// formatJSON indents a JSON document, or each document of a newline
+// delimited stream.
+func formatJSON(body []byte) string {
+	var parts []string
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	for {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err == io.EOF {
+			break
+		} else if err != nil {
+			return string(body)
+		}
+		buf := &bytes.Buffer{}
+		if err := json.Indent(buf, raw, "", "\t"); err != nil {
+			return string(body)
+		}
+		parts = append(parts, buf.String())
+	}
+	return strings.Join(parts, "\n")
+}
+
+// formatForm decodes a form encoded body into a table of keys and values.
+func formatForm(body []byte) string {
+	values, err := url.ParseQuery(strings.TrimSpace(string(body)))
+	if err != nil {
+		return string(body)
+	}
+	keys := make([]string, 0, len(values))
+	width := 0
+	for key := range values {
+		keys = append(keys, key)
+		width = max(width, len(key))
+	}
+	sort.Strings(keys)
+	builder := strings.Builder{}
+	for _, key := range keys {
+		for _, value := range values[key] {
+			fmt.Fprintf(&builder, "%-*s  %s\n", width, key, value)
+		}
+	}
+	return strings.TrimSuffix(builder.String(), "\n")
+}
+
+// formatMultipart lists the headers and content of each part of a multipart
+// body, summarizing binary content by its size.
+func formatMultipart(body []byte, boundary string) string {
+	builder := strings.Builder{}
+	reader := multipart.NewReader(bytes.NewReader(body), boundary)
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return string(body)
+		}
+		content, err := io.ReadAll(part)
+		if err != nil {
+			return string(body)
+		}
+		fmt.Fprintf(&builder, "--%s\n", boundary)
+		keys := make([]string, 0, len(part.Header))
+		for key := range part.Header {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			for _, value := range part.Header[key] {
+				fmt.Fprintf(&builder, "%s: %s\n", key, value)
+			}
+		}
+		builder.WriteString("\n")
+		if IsBinary(part.Header.Get("Content-Type"), content) {
+			fmt.Fprintf(&builder, "<%d bytes of binary data>\n", len(content))
+		} else {
+			builder.WriteString(strings.TrimSuffix(string(content), "\n") + "\n")
+		}
+	}
+	if builder.Len() == 0 {
+		return string(body)
+	}
+	fmt.Fprintf(&builder, "--%s--", boundary)
+	return builder.String()
+}
+
+// formatMarkup indents an XML document. HTML is parsed leniently, closing
+// void elements and resolving HTML entities.
+func formatMarkup(body []byte, html bool) string {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	next := decoder.RawToken
+	if html {
+		decoder.Strict = false
+		decoder.AutoClose = xml.HTMLAutoClose
+		decoder.Entity = xml.HTMLEntity
+		next = decoder.Token
+	}
+	w := &markupWriter{}
+	for {
+		token, err := next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return string(body)
+		}
+		w.write(xml.CopyToken(token))
+	}
+	w.flush()
+	if w.depth != 0 && !html {
+		return string(body)
+	}
+	return strings.TrimSuffix(w.String(), "\n")
+}
+
+// markupWriter writes markup tokens one per line. Elements holding only text
+// are kept on a single line.
+type markupWriter struct {
+	bytes.Buffer
+	depth   int
+	pending *xml.StartElement
+	text    string
+}
+
+func (w *markupWriter) write(token xml.Token) {
+	switch t := token.(type) {
+	case xml.StartElement:
+		w.flush()
+		w.pending = &t
+	case xml.CharData:
+		text := strings.TrimSpace(string(t))
+		if text == "" {
+			return
+		}
+		if w.pending != nil && w.text == "" {
+			w.text = text
+			return
+		}
+		w.flush()
+		w.line(escapeText(text))
+	case xml.EndElement:
+		if w.pending != nil && w.pending.Name == t.Name {
+			start := w.pending
+			text := w.text
+			w.pending, w.text = nil, ""
+			if text == "" {
+				w.line(fmt.Sprintf("<%s%s/>", name(start.Name), attrs(start.Attr)))
+				return
+			}
+			w.line(fmt.Sprintf("<%s%s>%s</%s>", name(start.Name), attrs(start.Attr), escapeText(text), name(t.Name)))
+			return
+		}
+		w.flush()
+		w.depth--
+		w.line(fmt.Sprintf("</%s>", name(t.Name)))
+	case xml.Comment:
+		w.flush()
+		w.line(fmt.Sprintf("<!--%s-->", t))
+	case xml.ProcInst:
+		w.flush()
+		w.line(fmt.Sprintf("<?%s %s?>", t.Target, t.Inst))
+	case xml.Directive:
+		w.flush()
+		w.line(fmt.Sprintf("<!%s>", t))
+	}
+}
+
+// flush writes the pending start element, which turned out to have children.
+func (w *markupWriter) flush() {
+	if w.pending == nil {
+		return
+	}
+	start, text := w.pending, w.text
+	w.pending, w.text = nil, ""
+	w.line(fmt.Sprintf("<%s%s>", name(start.Name), attrs(start.Attr)))
+	w.depth++
+	if text != "" {
+		w.line(escapeText(text))
+	}
+}
+
+func (w *markupWriter) line(text string) {
+	w.WriteString(strings.Repeat("  ", max(w.depth, 0)))
+	w.WriteString(text)
+	w.WriteString("\n")
+}
+
+func name(n xml.Name) string {
+	if n.Space == "" {
+		return n.Local
+	}
+	return n.Space + ":" + n.Local
+}
+
+func attrs(attributes []xml.Attr) string {
+	builder := strings.Builder{}
+	for _, attr := range attributes {
+		value := &bytes.Buffer{}
+		xml.EscapeText(value, []byte(attr.Value))
+		fmt.Fprintf(&builder, ` %s="%s"`, name(attr.Name), value)
+	}
+	return builder.String()
+}
+
+func escapeText(text string) string {
+	buf := &bytes.Buffer{}
+	xml.EscapeText(buf, []byte(text))
+	return strings.ReplaceAll(buf.String(), "&#xA;", "\n")
+}
@@ -0,0 +1,125 @@
+// Package query evaluates jq expressions, and the common subset of JSONPath
+// that maps onto them, against JSON documents.
+package query
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/itchyny/gojq"
+)
+
+var (
+	wildcardRegexp  = regexp.MustCompile(`\[\*\]|\.\*`)
+	quotedKeyRegexp = regexp.MustCompile(`\['((?:[^'\\]|\\.)*)'\]`)
+	recursiveRegexp = regexp.MustCompile(`\.\.([A-Za-z_][A-Za-z0-9_]*)`)
+)
+
+// Query is a compiled expression.
+type Query struct {
+	code *gojq.Code
+}
+
+// Compile parses the expression. Expressions starting with `$` are treated
+// as JSONPath, e.g. `$.users[*].name`, everything else as jq.
+func Compile(expr string) (*Query, error) {
+	expr = strings.TrimSpace(expr)
+	if strings.HasPrefix(expr, "$") {
+		expr = fromJSONPath(expr)
+	}
+	parsed, err := gojq.Parse(expr)
+	if err != nil {
+		return nil, err
+	}
+	code, err := gojq.Compile(parsed)
+	if err != nil {
+		return nil, err
+	}
+	return &Query{code: code}, nil
+}
+
+// Run returns every value the query produces for the document.
+func (q *Query) Run(document any) ([]any, error) {
+	var results []any
+	iter := q.code.Run(document)
+	for {
+		value, ok := iter.Next()
+		if !ok {
+			return results, nil
+		}
+		if err, ok := value.(error); ok {
+			return nil, err
+		}
+		results = append(results, value)
+	}
+}
+
+// Decode decodes a JSON document into the values queries run against.
+func Decode(body []byte) (any, error) {
+	var document any
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&document); err != nil {
+		return nil, err
+	}
+	return normalize(document), nil
+}
+
+// Format returns the results as indented JSON, one result after another like
+// the jq command line tool.
+func Format(results []any) (string, error) {
+	buf := &bytes.Buffer{}
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "\t")
+	for _, result := range results {
+		if err := encoder.Encode(result); err != nil {
+			return "", err
+		}
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// normalize converts json.Number values into the int and float64 values
+// gojq works with, keeping integers exact.
+func normalize(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, child := range v {
+			v[key] = normalize(child)
+		}
+	case []any:
+		for i, child := range v {
+			v[i] = normalize(child)
+		}
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return int(i)
+		}
+		f, _ := v.Float64()
+		return f
+	}
+	return value
+}
+
+// fromJSONPath translates a JSONPath expression into jq, supporting child
+// access, indexes, wildcards and recursive descent to a key.
+func fromJSONPath(path string) string {
+	expr := strings.TrimPrefix(path, "$")
+	expr = quotedKeyRegexp.ReplaceAllStringFunc(expr, func(match string) string {
+		key := quotedKeyRegexp.FindStringSubmatch(match)[1]
+		quoted, _ := json.Marshal(strings.ReplaceAll(key, `\'`, `'`))
+		return fmt.Sprintf("[%s]", quoted)
+	})
+	expr = wildcardRegexp.ReplaceAllString(expr, "[]")
+	expr = recursiveRegexp.ReplaceAllString(expr, ` | .. | .$1? // empty | `)
+	expr = strings.Trim(expr, " |")
+	if expr == "" || strings.HasPrefix(expr, "[") {
+		expr = "." + expr
+	}
+	// jq requires a dot before an index following a pipe
+	return strings.ReplaceAll(expr, "| [", "| .[")
+}
@@ -0,0 +1,108 @@
+package runner
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Row is an entry of a data set, mapping the column names to the values
+// bound as variables while the requests are run with it.
+type Row map[string]string
+
+// LoadData reads the rows of a data set. CSV files name the columns in their
+// first line, JSON files hold an array of objects whose non-string values are
+// bound as JSON text.
+func LoadData(path string) ([]Row, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var rows []Row
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".csv":
+		rows, err = parseCSV(data)
+	case ".json":
+		rows, err = parseJSON(data)
+	default:
+		return nil, fmt.Errorf("%s: unsupported data set %q, use .csv or .json", path, ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%s: no rows", path)
+	}
+	return rows, nil
+}
+
+func parseCSV(data []byte) ([]Row, error) {
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	columns := records[0]
+	rows := make([]Row, 0, len(records)-1)
+	for _, record := range records[1:] {
+		row := make(Row, len(columns))
+		for i, column := range columns {
+			row[strings.TrimSpace(column)] = record[i]
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func parseJSON(data []byte) ([]Row, error) {
+	var objects []map[string]json.RawMessage
+	if err := json.Unmarshal(data, &objects); err != nil {
+		return nil, fmt.Errorf("expected an array of objects: %w", err)
+	}
+	rows := make([]Row, 0, len(objects))
+	for _, object := range objects {
+		row := make(Row, len(object))
+		for name, raw := range object {
+			var value string
+			switch {
+			case string(raw) == "null":
+			case json.Unmarshal(raw, &value) == nil:
+			default:
+				value = string(raw)
+			}
+			row[name] = value
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// bind sets the values of the row in env and returns a function restoring
+// the variables the row replaced.
+func bind(env map[string]string, row Row) func() {
+	type previous struct {
+		value string
+		ok    bool
+	}
+	replaced := make(map[string]previous, len(row))
+	for name, value := range row {
+		old, ok := env[name]
+		replaced[name] = previous{value: old, ok: ok}
+		env[name] = value
+	}
+	return func() {
+		for name, p := range replaced {
+			if p.ok {
+				env[name] = p.value
+			} else {
+				delete(env, name)
+			}
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package runner
+
+import (
+	"maps"
+	"reflect"
+	"testing"
+)
+
+func TestParseCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    []Row
+		wantErr bool
+	}{
+		{
+			name: "header with spaces",
+			data: "name , email\nada, ada@example.com\n",
+			want: []Row{{"name": "ada", "email": " ada@example.com"}},
+		},
+		{
+			name: "quoted values",
+			data: "name,greeting\nada,\"hello, world\"\n",
+			want: []Row{{"name": "ada", "greeting": "hello, world"}},
+		},
+		{
+			name: "header only",
+			data: "name,email\n",
+			want: []Row{},
+		},
+		{
+			name: "empty",
+			data: "",
+			want: nil,
+		},
+		{
+			name:    "missing field",
+			data:    "name,email\nada\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseCSV([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseCSV() error = %v, want error %t", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseCSV() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    []Row
+		wantErr bool
+	}{
+		{
+			name: "string",
+			data: `[{"name": "ada"}]`,
+			want: []Row{{"name": "ada"}},
+		},
+		{
+			name: "null",
+			data: `[{"name": null}]`,
+			want: []Row{{"name": ""}},
+		},
+		{
+			name: "number and boolean",
+			data: `[{"age": 36, "admin": true}]`,
+			want: []Row{{"age": "36", "admin": "true"}},
+		},
+		{
+			name: "object and array",
+			data: `[{"address": {"city":"London"}, "tags": ["a","b"]}]`,
+			want: []Row{{"address": `{"city":"London"}`, "tags": `["a","b"]`}},
+		},
+		{
+			name: "several rows",
+			data: `[{"id": 1}, {"id": 2}]`,
+			want: []Row{{"id": "1"}, {"id": "2"}},
+		},
+		{
+			name:    "not an array",
+			data:    `{"name": "ada"}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseJSON([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseJSON() error = %v, want error %t", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseJSON() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBind(t *testing.T) {
+	env := map[string]string{"host": "localhost", "name": "grace", "token": ""}
+	original := maps.Clone(env)
+	restore := bind(env, Row{"name": "ada", "email": "ada@example.com", "token": "abc"})
+	want := map[string]string{"host": "localhost", "name": "ada", "email": "ada@example.com", "token": "abc"}
+	if !maps.Equal(env, want) {
+		t.Errorf("bound env = %v, want %v", env, want)
+	}
+	restore()
+	if !maps.Equal(env, original) {
+		t.Errorf("restored env = %v, want %v", env, original)
+	}
+}
@@ -0,0 +1,43 @@
+package runner
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/go-rq/req/internal/httpfile"
+)
+
+// Filter selects the requests of a run. The zero value selects every
+// request. Prerequisites of a selected request are sent regardless.
+type Filter struct {
+	// Tags selects the requests with any of the tags, SkipTags leaves out the
+	// requests with any of them.
+	Tags     []string
+	SkipTags []string
+
+	// Name selects the requests whose name matches.
+	Name *regexp.Regexp
+
+	// Methods selects the requests with any of the methods, ignoring case.
+	Methods []string
+}
+
+// Match reports whether the filter selects the request.
+func (f Filter) Match(request httpfile.Request) bool {
+	if len(f.Tags) > 0 && !slices.ContainsFunc(f.Tags, request.HasTag) {
+		return false
+	}
+	if slices.ContainsFunc(f.SkipTags, request.HasTag) {
+		return false
+	}
+	if f.Name != nil && !f.Name.MatchString(request.DisplayName()) {
+		return false
+	}
+	if len(f.Methods) > 0 && !slices.ContainsFunc(f.Methods, func(method string) bool {
+		return strings.EqualFold(method, request.Method)
+	}) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,55 @@
+package runner
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/go-rq/req/internal/httpfile"
+	"github.com/go-rq/rq"
+)
+
+func TestFilterMatch(t *testing.T) {
+	request := httpfile.Request{
+		Request: rq.Request{Name: "Create User", Method: "POST"},
+		Directives: []httpfile.Directive{
+			{Name: "tag", Value: "users, smoke"},
+			{Name: "tag", Value: "Slow"},
+		},
+	}
+	tests := []struct {
+		name   string
+		filter Filter
+		want   bool
+	}{
+		{"zero value", Filter{}, true},
+		{"tag", Filter{Tags: []string{"smoke"}}, true},
+		{"tag from a second directive", Filter{Tags: []string{"slow"}}, true},
+		{"any of the tags", Filter{Tags: []string{"orders", "USERS"}}, true},
+		{"missing tag", Filter{Tags: []string{"orders"}}, false},
+		{"skip tag", Filter{SkipTags: []string{"slow"}}, false},
+		{"skip tag wins over tag", Filter{Tags: []string{"users"}, SkipTags: []string{"smoke"}}, false},
+		{"other skip tag", Filter{Tags: []string{"users"}, SkipTags: []string{"orders"}}, true},
+		{"name", Filter{Name: regexp.MustCompile(`^Create`)}, true},
+		{"other name", Filter{Name: regexp.MustCompile(`^Delete`)}, false},
+		{"method ignoring case", Filter{Methods: []string{"get", "post"}}, true},
+		{"other method", Filter{Methods: []string{"GET"}}, false},
+		{"every criterion", Filter{
+			Tags:     []string{"users"},
+			SkipTags: []string{"orders"},
+			Name:     regexp.MustCompile(`User`),
+			Methods:  []string{"POST"},
+		}, true},
+		{"every criterion but the method", Filter{
+			Tags:    []string{"users"},
+			Name:    regexp.MustCompile(`User`),
+			Methods: []string{"PUT"},
+		}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Match(request); got != tt.want {
+				t.Errorf("Match() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package runner
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/go-rq/rq"
+)
+
+// Write prints the report as text, one line per case preceded by the
+// prerequisites sent for it, followed by the timing, assertions, logs and
+// saved output of the case and a summary.
+func (r Report) Write(w io.Writer) {
+	for _, file := range r.Files {
+		if file.Err == nil && len(file.Cases) == 0 {
+			// no request of the file was selected
+			continue
+		}
+		fmt.Fprintln(w, file.Path)
+		if file.Err != nil {
+			fmt.Fprintf(w, "  FAIL  %s\n", file.Err)
+		}
+		for _, c := range file.Cases {
+			writeCase(w, c)
+		}
+		fmt.Fprintln(w)
+	}
+	passed, failed, skipped := r.Counts()
+	fmt.Fprintf(w, "%d passed, %d failed, %d skipped in %s\n", passed, failed, skipped, r.Duration.Round(time.Millisecond))
+}
+
+func writeCase(w io.Writer, c Case) {
+	for _, prerequisite := range c.Result.Prerequisites {
+		fmt.Fprintf(w, "  STEP  %s\n", prerequisite)
+		if prerequisite.Err == nil && !prerequisite.Cached {
+			writeDetails(w, prerequisite.Request.Logs, prerequisite.Result.Response.PostRequestAssertions)
+		}
+	}
+	switch {
+	case c.Skipped():
+		fmt.Fprintf(w, "  SKIP  %s\n", c.Name())
+	case c.Err != nil:
+		fmt.Fprintf(w, "  FAIL  %s: %s\n", c.Name(), c.Err)
+	default:
+		result := "PASS"
+		if !c.Passed() {
+			result = "FAIL"
+		}
+		fmt.Fprintf(w, "  %s  %s: %s%s\n", result, c.Name(), c.Result.Response.Status, duration(c))
+	}
+	if timing := timing(c); timing != "" {
+		fmt.Fprintf(w, "        %s\n", timing)
+	}
+	for _, poll := range c.Result.Polls {
+		fmt.Fprintf(w, "        %s\n", poll)
+	}
+	writeDetails(w, c.Request.Logs, c.Assertions())
+	switch {
+	case c.Result.OutputErr != nil:
+		fmt.Fprintf(w, "        could not save body: %s\n", c.Result.OutputErr)
+	case c.Result.Output != "":
+		fmt.Fprintf(w, "        saved body to %s\n", c.Result.Output)
+	}
+}
+
+func writeDetails(w io.Writer, logs []string, assertions []rq.Assertion) {
+	for _, log := range logs {
+		for _, line := range strings.Split(strings.TrimRight(log, "\n"), "\n") {
+			fmt.Fprintf(w, "        %s\n", line)
+		}
+	}
+	for _, assertion := range assertions {
+		mark := "✓"
+		if !assertion.Success {
+			mark = "✗"
+		}
+		fmt.Fprintf(w, "        %s %s\n", mark, assertion.Message)
+	}
+}
+
+// timing lists the phases of the last attempt of the case, e.g.
+// `DNS Lookup 1ms, TCP Connect 1ms, Time to First Byte 3ms, ...`.
+func timing(c Case) string {
+	if len(c.Result.Attempts) == 0 {
+		return ""
+	}
+	timing := c.Result.Attempts[len(c.Result.Attempts)-1].Timing
+	phases := make([]string, len(timing.Phases))
+	for i, phase := range timing.Phases {
+		phases[i] = fmt.Sprintf("%s %s", phase.Name, phase.Duration.Round(time.Microsecond))
+	}
+	if timing.ConnectionReused {
+		phases = append(phases, "connection reused")
+	}
+	return strings.Join(phases, ", ")
+}
+
+func duration(c Case) string {
+	if len(c.Result.Attempts) == 0 {
+		return ""
+	}
+	return " in " + c.Result.Attempts[len(c.Result.Attempts)-1].Duration.Round(time.Millisecond).String()
+}
@@ -0,0 +1,335 @@
+// Package runner sends the requests of .http files without the TUI, e.g. in
+// CI, and reports the outcome of each send:
+//
+//	req run --data users.csv ./api
+//
+// The requests of a file are sent in order, sharing the environment so
+// variables set by the scripts of a request are available to the following
+// ones. With --parallel several files are run at the same time, each with a
+// copy of the environment. A data set given with --data runs the requests of
+// every file once per row, and a request declaring `# @data <path>` is sent
+// once per row of its own data set, with the columns of the row bound as
+// variables.
+package runner
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-rq/req/internal/client"
+	"github.com/go-rq/req/internal/httpfile"
+	"github.com/go-rq/req/internal/template"
+	"github.com/go-rq/rq"
+)
+
+// Options configure a run.
+type Options struct {
+	// Data is the data set the requests of every file are run with, once per
+	// row. Without it they are run once.
+	Data []Row
+
+	// Parallel is the number of files run at the same time. Files run in
+	// parallel do not share the environment, each starts from a copy of it.
+	Parallel int
+
+	// Filter selects the requests that are run.
+	Filter Filter
+}
+
+// Case is a single send of a request.
+type Case struct {
+	Request httpfile.Request
+
+	// Rows are the 1-based indices of the data set rows the request was sent
+	// with, the row of the --data set before the row of its @data set.
+	Rows []int
+
+	Result *client.Result
+	Err    error
+}
+
+// Name returns the name of the request followed by the rows it was sent
+// with, e.g. `Create User [row 2]`.
+func (c Case) Name() string {
+	name := c.Request.DisplayName()
+	for _, row := range c.Rows {
+		name += fmt.Sprintf(" [row %d]", row)
+	}
+	return name
+}
+
+// Assertions returns the pre and post request assertions of the send.
+func (c Case) Assertions() []rq.Assertion {
+	assertions := c.Request.PreRequestAssertions
+	if c.Result != nil && c.Result.Response != nil {
+		assertions = append(assertions[:len(assertions):len(assertions)], c.Result.Response.PostRequestAssertions...)
+	}
+	return assertions
+}
+
+// Skipped reports whether the pre-request script skipped the request.
+func (c Case) Skipped() bool {
+	return errors.Is(c.Err, rq.ErrSkipped)
+}
+
+// Passed reports whether the request was sent, its response saved if it
+// redirects it to a file, and all of its assertions passed.
+func (c Case) Passed() bool {
+	if c.Err != nil || c.Result.OutputErr != nil {
+		return false
+	}
+	for _, assertion := range c.Assertions() {
+		if !assertion.Success {
+			return false
+		}
+	}
+	return true
+}
+
+// File is the outcome of running the requests of a file selected by the
+// filter. Err is set if the file could not be read.
+type File struct {
+	Path  string
+	Cases []Case
+	Err   error
+}
+
+// Report is the outcome of a run.
+type Report struct {
+	Files    []File
+	Duration time.Duration
+}
+
+// Counts returns the number of cases that passed, failed and were skipped.
+// Files that could not be read count as failed.
+func (r Report) Counts() (passed, failed, skipped int) {
+	for _, file := range r.Files {
+		if file.Err != nil {
+			failed++
+		}
+		for _, c := range file.Cases {
+			switch {
+			case c.Skipped():
+				skipped++
+			case c.Passed():
+				passed++
+			default:
+				failed++
+			}
+		}
+	}
+	return passed, failed, skipped
+}
+
+// Passed reports whether nothing failed.
+func (r Report) Passed() bool {
+	_, failed, _ := r.Counts()
+	return failed == 0
+}
+
+// Files returns the .http files at the given paths, walking directories.
+func Files(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		err = filepath.WalkDir(path, func(path string, entry os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !entry.IsDir() && strings.HasSuffix(path, ".http") {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// Run sends the requests of the files with the environment and client of
+// the context, stopping early if the context is cancelled. The files are
+// reported in the order given, however many of them run in parallel.
+func Run(ctx context.Context, files []string, options Options) Report {
+	start := time.Now()
+	r := &run{options: options, responses: map[string]*template.Response{}}
+	ctx = template.WithLookup(ctx, r.lookup)
+	env := rq.GetEnvironment(ctx)
+	report := Report{Files: make([]File, len(files))}
+	var (
+		wg      sync.WaitGroup
+		indices = make(chan int)
+	)
+	for worker := 0; worker < max(options.Parallel, 1); worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				if ctx.Err() != nil {
+					continue
+				}
+				fileEnv := env
+				if options.Parallel > 1 {
+					fileEnv = maps.Clone(env)
+				}
+				report.Files[i] = r.file(rq.WithEnvironment(ctx, fileEnv), files[i])
+			}
+		}()
+	}
+send:
+	for i := range files {
+		select {
+		case <-ctx.Done():
+			break send
+		case indices <- i:
+		}
+	}
+	close(indices)
+	wg.Wait()
+	// leave out the files not started before a cancellation
+	report.Files = slices.DeleteFunc(report.Files, func(file File) bool {
+		return file.Path == ""
+	})
+	report.Duration = time.Since(start)
+	return report
+}
+
+type run struct {
+	options Options
+
+	mu sync.Mutex
+	// responses holds the latest response of every request sent, for
+	// references to other requests
+	responses map[string]*template.Response
+}
+
+func (r *run) file(ctx context.Context, path string) File {
+	file := File{Path: path}
+	requests, err := httpfile.ParseFile(path)
+	if err != nil {
+		file.Err = err
+		return file
+	}
+	requests = slices.DeleteFunc(requests, func(request httpfile.Request) bool {
+		return !r.options.Filter.Match(request)
+	})
+	env := rq.GetEnvironment(ctx)
+	if r.options.Data == nil {
+		for _, request := range requests {
+			file.Cases = append(file.Cases, r.request(ctx, request, nil)...)
+		}
+		return file
+	}
+	for i, row := range r.options.Data {
+		restore := bind(env, row)
+		// prerequisites are sent again for every row, with its variables
+		rowCtx := client.WithResultCache(ctx)
+		for _, request := range requests {
+			file.Cases = append(file.Cases, r.request(rowCtx, request, []int{i + 1})...)
+		}
+		restore()
+	}
+	return file
+}
+
+// request sends the request once, or once per row of its @data set.
+func (r *run) request(ctx context.Context, request httpfile.Request, rows []int) []Case {
+	path, ok := request.DataFile()
+	if !ok {
+		return []Case{r.send(ctx, request, rows)}
+	}
+	data, err := LoadData(path)
+	if err != nil {
+		return []Case{{Request: request, Rows: rows, Result: &client.Result{}, Err: err}}
+	}
+	env := rq.GetEnvironment(ctx)
+	var cases []Case
+	for i, row := range data {
+		restore := bind(env, row)
+		cases = append(cases, r.send(client.WithResultCache(ctx), request, append(rows[:len(rows):len(rows)], i+1)))
+		restore()
+	}
+	return cases
+}
+
+func (r *run) send(ctx context.Context, request httpfile.Request, rows []int) Case {
+	if err := ctx.Err(); err != nil {
+		return Case{Request: request, Rows: rows, Result: &client.Result{}, Err: err}
+	}
+	result, err := client.GetClient(ctx).Send(ctx, &request)
+	for _, prerequisite := range result.Prerequisites {
+		if !prerequisite.Cached && prerequisite.Result != nil {
+			r.store(prerequisite.Request, prerequisite.Result)
+		}
+	}
+	if err == nil {
+		r.store(request, result)
+	}
+	return Case{Request: request, Rows: rows, Result: result, Err: err}
+}
+
+// store keeps the response of the result for references to the request.
+func (r *run) store(request httpfile.Request, result *client.Result) {
+	resp := result.Response
+	if resp == nil {
+		return
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return
+	}
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.responses[request.File+"#"+request.DisplayName()] = &template.Response{Header: resp.Header, Body: body}
+}
+
+// lookup resolves references to the latest responses of the run, sending
+// requests without a response if send is set.
+func (r *run) lookup(ctx context.Context, file, name string, send bool) (*template.Response, error) {
+	r.mu.Lock()
+	resp, ok := r.responses[file+"#"+name]
+	r.mu.Unlock()
+	if ok {
+		return resp, nil
+	}
+	if !send {
+		return nil, template.ErrNoResponse
+	}
+	requests, err := httpfile.ParseFile(file)
+	if err != nil {
+		return nil, err
+	}
+	idx := slices.IndexFunc(requests, func(request httpfile.Request) bool {
+		return request.DisplayName() == name
+	})
+	if idx < 0 {
+		return nil, fmt.Errorf("no request named %q in %s", name, file)
+	}
+	c := r.send(ctx, requests[idx], nil)
+	if c.Err != nil {
+		return nil, c.Err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.responses[file+"#"+name], nil
+}
@@ -0,0 +1,145 @@
+package template
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dynamic resolves a dynamic variable such as `$uuid` or `$randomInt 1 10`,
+// generating a new value for every reference.
+func (r *Resolver) dynamic(expr string) (string, error) {
+	fields := strings.Fields(expr)
+	name, args := fields[0], fields[1:]
+	switch name {
+	case "$uuid", "$random.uuid":
+		return newUUID()
+	case "$timestamp":
+		return strconv.FormatInt(time.Now().Unix(), 10), nil
+	case "$isoTimestamp":
+		return time.Now().UTC().Format("2006-01-02T15:04:05.000Z07:00"), nil
+	case "$randomInt", "$random.integer":
+		return randomInt(args)
+	case "$random.email":
+		suffix, err := randomHex(4)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("user-%s@example.com", suffix), nil
+	case "$processEnv":
+		if len(args) != 1 {
+			return "", fmt.Errorf("expected the name of an environment variable")
+		}
+		value, ok := os.LookupEnv(args[0])
+		if !ok {
+			return "", fmt.Errorf("environment variable %s is not set", args[0])
+		}
+		return value, nil
+	case "$dotenv":
+		if len(args) != 1 {
+			return "", fmt.Errorf("expected the name of a variable in the .env file")
+		}
+		return r.dotenvValue(args[0])
+	}
+	return "", fmt.Errorf("unknown dynamic variable %s", name)
+}
+
+// newUUID returns a random version 4 UUID.
+func newUUID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
+
+// randomInt returns a random integer from min up to, but excluding, max,
+// which default to 0 and 1000.
+func randomInt(args []string) (string, error) {
+	bounds := []int64{0, 1000}
+	switch len(args) {
+	case 0:
+	case 2:
+		for i, arg := range args {
+			bound, err := strconv.ParseInt(arg, 10, 64)
+			if err != nil {
+				return "", fmt.Errorf("invalid bound %q", arg)
+			}
+			bounds[i] = bound
+		}
+	default:
+		return "", fmt.Errorf("expected no arguments or a min and max")
+	}
+	if bounds[1] <= bounds[0] {
+		return "", fmt.Errorf("max %d is not greater than min %d", bounds[1], bounds[0])
+	}
+	n, err := rand.Int(rand.Reader, big.NewInt(bounds[1]-bounds[0]))
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(bounds[0]+n.Int64(), 10), nil
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// dotenvValue returns a variable defined in the .env file next to the .http
+// file.
+func (r *Resolver) dotenvValue(name string) (string, error) {
+	if r.dotenv == nil {
+		env, err := readDotenv(filepath.Join(r.dir, ".env"))
+		if err != nil {
+			return "", err
+		}
+		r.dotenv = env
+	}
+	value, ok := r.dotenv[name]
+	if !ok {
+		return "", fmt.Errorf("%s is not defined in %s", name, filepath.Join(r.dir, ".env"))
+	}
+	return value, nil
+}
+
+// readDotenv reads the KEY=value lines of a .env file, ignoring blank lines
+// and comments. Values may be quoted.
+func readDotenv(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	env := map[string]string{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		if !ok {
+			return nil, fmt.Errorf("%s: invalid line: %s", path, line)
+		}
+		value = strings.TrimSpace(value)
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			value = unquoted
+		} else if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+			value = value[1 : len(value)-1]
+		}
+		env[strings.TrimSpace(key)] = value
+	}
+	return env, scanner.Err()
+}
@@ -0,0 +1,202 @@
+package template
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// functions are the helpers available in expressions along with the minimum
+// and maximum number of arguments they take. Every argument and result is a
+// string.
+var functions = map[string]struct {
+	minArgs, maxArgs int
+	call             func(args []string) (string, error)
+}{
+	"base64": {1, 1, func(args []string) (string, error) {
+		return base64.StdEncoding.EncodeToString([]byte(args[0])), nil
+	}},
+	"urlencode": {1, 1, func(args []string) (string, error) {
+		return url.QueryEscape(args[0]), nil
+	}},
+	"sha256": {1, 1, func(args []string) (string, error) {
+		sum := sha256.Sum256([]byte(args[0]))
+		return hex.EncodeToString(sum[:]), nil
+	}},
+	"hmac": {2, 2, func(args []string) (string, error) {
+		mac := hmac.New(sha256.New, []byte(args[0]))
+		mac.Write([]byte(args[1]))
+		return hex.EncodeToString(mac.Sum(nil)), nil
+	}},
+	// now formats the current time with a Go layout, RFC 3339 by default
+	"now": {0, 1, func(args []string) (string, error) {
+		if len(args) == 0 {
+			return time.Now().Format(time.RFC3339), nil
+		}
+		return time.Now().Format(args[0]), nil
+	}},
+}
+
+// evaluate computes an expression made of string literals, environment
+// variables, function calls and concatenations with `+`, e.g.
+// `base64(user + ":" + pass)`.
+func (r *Resolver) evaluate(expr string) (string, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return "", err
+	}
+	p := &parser{tokens: tokens, env: r.env}
+	value, err := p.expr()
+	if err != nil {
+		return "", err
+	}
+	if p.pos < len(p.tokens) {
+		return "", fmt.Errorf("unexpected %q", p.tokens[p.pos])
+	}
+	return value, nil
+}
+
+// tokenize splits an expression into identifiers, numbers, quoted strings
+// and the punctuation ( ) , +.
+func tokenize(expr string) ([]string, error) {
+	var tokens []string
+	for i := 0; i < len(expr); {
+		c := rune(expr[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case strings.ContainsRune("(),+", c):
+			tokens = append(tokens, string(c))
+			i++
+		case c == '"':
+			end := i + 1
+			for end < len(expr) && expr[end] != '"' {
+				if expr[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(expr) {
+				return nil, fmt.Errorf("unterminated string %s", expr[i:])
+			}
+			tokens = append(tokens, expr[i:end+1])
+			i = end + 1
+		case c == '_' || unicode.IsLetter(c) || unicode.IsDigit(c):
+			end := i
+			for end < len(expr) && (expr[end] == '_' || expr[end] == '.' || expr[end] == '-' ||
+				unicode.IsLetter(rune(expr[end])) || unicode.IsDigit(rune(expr[end]))) {
+				end++
+			}
+			tokens = append(tokens, expr[i:end])
+			i = end
+		default:
+			return nil, fmt.Errorf("unexpected character %q", c)
+		}
+	}
+	return tokens, nil
+}
+
+type parser struct {
+	tokens []string
+	pos    int
+	env    map[string]string
+}
+
+func (p *parser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *parser) next() string {
+	token := p.peek()
+	p.pos++
+	return token
+}
+
+// expr parses operands joined with `+`.
+func (p *parser) expr() (string, error) {
+	value, err := p.operand()
+	if err != nil {
+		return "", err
+	}
+	for p.peek() == "+" {
+		p.next()
+		right, err := p.operand()
+		if err != nil {
+			return "", err
+		}
+		value += right
+	}
+	return value, nil
+}
+
+// operand parses a string, number, variable, function call or parenthesized
+// expression.
+func (p *parser) operand() (string, error) {
+	token := p.next()
+	switch {
+	case token == "":
+		return "", fmt.Errorf("unexpected end of expression")
+	case token == "(":
+		value, err := p.expr()
+		if err != nil {
+			return "", err
+		}
+		if p.next() != ")" {
+			return "", fmt.Errorf("missing )")
+		}
+		return value, nil
+	case strings.HasPrefix(token, `"`):
+		return strconv.Unquote(token)
+	case unicode.IsDigit(rune(token[0])):
+		return token, nil
+	case strings.ContainsAny(token, "(),+"):
+		return "", fmt.Errorf("unexpected %q", token)
+	case p.peek() == "(":
+		return p.call(token)
+	}
+	value, ok := p.env[token]
+	if !ok {
+		return "", fmt.Errorf("undefined variable %s", token)
+	}
+	return value, nil
+}
+
+// call parses the arguments of a call to the function name and calls it.
+func (p *parser) call(name string) (string, error) {
+	p.next()
+	var args []string
+	for p.peek() != ")" {
+		if len(args) > 0 {
+			if p.next() != "," {
+				return "", fmt.Errorf("expected , between the arguments of %s", name)
+			}
+		}
+		arg, err := p.expr()
+		if err != nil {
+			return "", err
+		}
+		args = append(args, arg)
+	}
+	p.next()
+	function, ok := functions[name]
+	if !ok {
+		return "", fmt.Errorf("unknown function %s", name)
+	}
+	if len(args) < function.minArgs || len(args) > function.maxArgs {
+		if function.minArgs == function.maxArgs {
+			return "", fmt.Errorf("%s expects %d argument(s), got %d", name, function.minArgs, len(args))
+		}
+		return "", fmt.Errorf("%s expects %d to %d arguments, got %d", name, function.minArgs, function.maxArgs, len(args))
+	}
+	return function.call(args)
+}
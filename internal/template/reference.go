@@ -0,0 +1,124 @@
+package template
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-rq/req/internal/query"
+)
+
+// referenceSeparator separates the name of the referenced request from the
+// part of its response, e.g. `Login.response.body.$.token`.
+const referenceSeparator = ".response."
+
+type (
+	lookupContextKey  struct{}
+	sendingContextKey struct{}
+)
+
+// Response is the part of a response references resolve against.
+type Response struct {
+	Header http.Header
+	Body   []byte
+}
+
+// Lookup returns the most recent response of the request named name in the
+// .http file, sending the request first if it has none yet and send is set.
+type Lookup func(ctx context.Context, file, name string, send bool) (*Response, error)
+
+// ErrNoResponse is returned by a Lookup for a request without a response.
+var ErrNoResponse = errors.New("no response yet")
+
+// WithLookup returns a new context in which response references are
+// resolved with lookup.
+func WithLookup(ctx context.Context, lookup Lookup) context.Context {
+	return context.WithValue(ctx, lookupContextKey{}, lookup)
+}
+
+func getLookup(ctx context.Context) Lookup {
+	if lookup, ok := ctx.Value(lookupContextKey{}).(Lookup); ok {
+		return lookup
+	}
+	return func(context.Context, string, string, bool) (*Response, error) {
+		return nil, ErrNoResponse
+	}
+}
+
+// reference resolves `Name.response.body`, optionally followed by a JSONPath
+// or jq expression, or `Name.response.headers.Header-Name` against the most
+// recent response of the request called Name in the same .http file.
+func (r *Resolver) reference(expr string) (string, error) {
+	name, path, _ := strings.Cut(expr, referenceSeparator)
+	name = strings.TrimSpace(name)
+	file := r.request.File
+	if file == "" {
+		return "", fmt.Errorf("%s is not in a file", r.request.DisplayName())
+	}
+	// guard against requests referencing each other while being sent
+	key := file + "#" + name
+	sending, _ := r.ctx.Value(sendingContextKey{}).([]string)
+	for _, pending := range sending {
+		if pending == key {
+			return "", fmt.Errorf("circular reference to %s", name)
+		}
+	}
+	ctx := context.WithValue(r.ctx, sendingContextKey{}, append(sending[:len(sending):len(sending)], key))
+	resp, err := getLookup(ctx)(ctx, file, name, r.SendReferences)
+	if errors.Is(err, ErrNoResponse) {
+		return "", fmt.Errorf("%s has no response yet", name)
+	}
+	if err != nil {
+		return "", fmt.Errorf("sending %s: %w", name, err)
+	}
+	switch {
+	case path == "body":
+		return string(resp.Body), nil
+	case strings.HasPrefix(path, "body."):
+		return selectJSON(resp.Body, strings.TrimPrefix(path, "body."))
+	case strings.HasPrefix(path, "headers."):
+		header := strings.TrimPrefix(path, "headers.")
+		values := resp.Header.Values(header)
+		if len(values) == 0 {
+			return "", fmt.Errorf("%s: response has no %s header", name, header)
+		}
+		return strings.Join(values, ", "), nil
+	}
+	return "", fmt.Errorf("expected body or headers after %s%s", name, referenceSeparator)
+}
+
+// selectJSON returns the value the expression selects from the JSON body.
+// Strings are returned as they are, anything else as JSON. Expressions not
+// starting with `$` are taken as paths below the root, e.g. `token`.
+func selectJSON(body []byte, expr string) (string, error) {
+	if !strings.HasPrefix(expr, "$") {
+		expr = "$." + expr
+	}
+	q, err := query.Compile(expr)
+	if err != nil {
+		return "", err
+	}
+	document, err := query.Decode(body)
+	if err != nil {
+		return "", fmt.Errorf("response body is not valid JSON: %w", err)
+	}
+	results, err := q.Run(document)
+	if err != nil {
+		return "", err
+	}
+	var value any = results
+	switch len(results) {
+	case 0:
+		return "", fmt.Errorf("%s matches nothing in the response body", expr)
+	case 1:
+		value = results[0]
+	}
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	encoded, err := json.Marshal(value)
+	return string(encoded), err
+}
@@ -0,0 +1,125 @@
+// Package template resolves the {{...}} expressions in requests that rq does
+// not substitute itself, i.e. everything but references to environment
+// variables: dynamic variables, references to the responses of other
+// requests and calls to helper functions. These are resolved when a request
+// is sent and in previews, e.g.
+//
+//	POST {{host}}/users
+//	X-Request-Id: {{$uuid}}
+//	Authorization: Bearer {{Login.response.body.$.token}}
+//	X-Signature: {{hmac(secret, user)}}
+package template
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/go-rq/req/internal/httpfile"
+	"github.com/go-rq/rq"
+)
+
+var (
+	expressionRegexp = regexp.MustCompile(`{{(.*?)}}`)
+	callRegexp       = regexp.MustCompile(`([A-Za-z_][A-Za-z0-9_]*)\s*\(`)
+)
+
+// Variable is an expression along with the value it resolved to.
+type Variable struct {
+	Name  string
+	Value string
+}
+
+// Resolver resolves the expressions of a request.
+type Resolver struct {
+	ctx     context.Context
+	request *httpfile.Request
+	env     map[string]string
+	dir     string
+
+	// SendReferences sends referenced requests without a response first.
+	SendReferences bool
+
+	// dotenv holds the variables of the .env file once it has been read
+	dotenv map[string]string
+
+	// Variables records every expression resolved, in order, so that a
+	// request can be reproduced with the same values.
+	Variables []Variable
+}
+
+// New returns a resolver for the request sent with the environment and
+// responses held by ctx.
+func New(ctx context.Context, request *httpfile.Request) *Resolver {
+	return &Resolver{ctx: ctx, request: request, env: rq.GetEnvironment(ctx), dir: request.Dir}
+}
+
+// Expand resolves the dynamic variables, response references and function
+// calls in text.
+// References to environment variables are left in place for rq.
+func (r *Resolver) Expand(text string) (string, error) {
+	var err error
+	expanded := expressionRegexp.ReplaceAllStringFunc(text, func(match string) string {
+		if err != nil {
+			return match
+		}
+		expr := strings.TrimSpace(match[2 : len(match)-2])
+		var value string
+		switch {
+		case strings.HasPrefix(expr, "$"):
+			value, err = r.dynamic(expr)
+		case strings.Contains(expr, referenceSeparator):
+			value, err = r.reference(expr)
+		case isCall(expr):
+			value, err = r.evaluate(expr)
+		default:
+			return match
+		}
+		if err != nil {
+			err = fmt.Errorf("{{%s}}: %w", expr, err)
+			return match
+		}
+		r.Variables = append(r.Variables, Variable{Name: expr, Value: value})
+		return value
+	})
+	return expanded, err
+}
+
+// isCall reports whether the first identifier followed by `(` in the
+// expression is a helper function. Other expressions with parentheses are
+// left in place for rq.
+func isCall(expr string) bool {
+	match := callRegexp.FindStringSubmatch(expr)
+	if match == nil {
+		return false
+	}
+	_, ok := functions[match[1]]
+	return ok
+}
+
+// Request returns a copy of the request with the expressions in its method,
+// URL, headers and body resolved.
+func (r *Resolver) Request(request rq.Request) (rq.Request, error) {
+	var err error
+	if request.Method, err = r.Expand(request.Method); err != nil {
+		return request, err
+	}
+	if request.URL, err = r.Expand(request.URL); err != nil {
+		return request, err
+	}
+	headers := make(rq.Headers, len(request.Headers))
+	for i, header := range request.Headers {
+		if headers[i].Key, err = r.Expand(header.Key); err != nil {
+			return request, err
+		}
+		if headers[i].Value, err = r.Expand(header.Value); err != nil {
+			return request, err
+		}
+	}
+	request.Headers = headers
+	if request.Body, err = r.Expand(request.Body); err != nil {
+		return request, err
+	}
+	return request, nil
+}
@@ -0,0 +1,96 @@
+package tui
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/go-rq/req/internal/bench"
+	"github.com/rivo/tview"
+)
+
+const (
+	benchHelp     = "Esc: Stop"
+	benchDoneHelp = "Esc: Quit"
+
+	// benchRefresh is how often the summary of a running benchmark is redrawn.
+	benchRefresh = 250 * time.Millisecond
+)
+
+// BenchView shows the summary of a running benchmark with a histogram of the
+// latencies, redrawn as the samples come in.
+type BenchView struct {
+	app      *tview.Application
+	text     *tview.TextView
+	helpInfo *tview.TextView
+	layout   *tview.Frame
+	recorder *bench.Recorder
+	cancel   context.CancelFunc
+	done     bool
+}
+
+// NewBenchView returns a view of the benchmark recorded by recorder. Esc
+// calls cancel to stop the benchmark and stops the application once it has.
+func NewBenchView(app *tview.Application, title string, recorder *bench.Recorder, cancel context.CancelFunc) *BenchView {
+	view := &BenchView{
+		app:      app,
+		text:     tview.NewTextView(),
+		helpInfo: tview.NewTextView(),
+		recorder: recorder,
+		cancel:   cancel,
+	}
+	view.text.SetBorder(true).SetTitle("Summary")
+	view.helpInfo.SetTextAlign(tview.AlignCenter).SetText(benchHelp)
+	grid := tview.NewGrid().SetRows(0, 1)
+	grid.AddItem(view.text, 0, 0, 1, 1, 0, 0, true)
+	grid.AddItem(view.helpInfo, 1, 0, 1, 1, 0, 0, false)
+	view.layout = tview.NewFrame(grid)
+	view.layout.AddText(title, true, tview.AlignCenter, tcell.ColorForestGreen)
+	view.text.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEsc {
+			if view.done {
+				view.app.Stop()
+			} else {
+				view.cancel()
+			}
+			return nil
+		}
+		return event
+	})
+	return view
+}
+
+// refresh redraws the summary until the benchmark is done.
+func (view *BenchView) refresh() {
+	ticker := time.NewTicker(benchRefresh)
+	defer ticker.Stop()
+	for range ticker.C {
+		summary := view.recorder.Summary()
+		view.app.QueueUpdateDraw(func() {
+			view.render(summary)
+		})
+		if summary.Done {
+			return
+		}
+	}
+}
+
+func (view *BenchView) render(summary bench.Summary) {
+	_, _, width, _ := view.text.GetInnerRect()
+	var text strings.Builder
+	summary.Write(&text, max(width-26, 10))
+	view.text.SetText(text.String())
+	if summary.Done {
+		view.done = true
+		view.helpInfo.SetText(benchDoneHelp)
+	}
+}
+
+func (view *BenchView) Mount(app *tview.Application) {
+	if view.text.GetText(false) == "" {
+		go view.refresh()
+	}
+	app.SetRoot(view.layout, true)
+	app.SetFocus(view.text)
+}
@@ -0,0 +1,70 @@
+package tui
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"strings"
+
+	"github.com/go-rq/req/internal/pretty"
+	"github.com/rivo/tview"
+)
+
+const (
+	// hexDumpLimit is the number of bytes of a binary body shown in the
+	// preview, the whole body can be saved to a file.
+	hexDumpLimit = 4096
+
+	// imagePreviewWidth is the maximum width in cells of image previews.
+	imagePreviewWidth = 80
+)
+
+// binaryPreview describes a binary body instead of printing its bytes. Images
+// are rendered at a low resolution, anything else as a hex dump.
+func binaryPreview(contentType string, body []byte) string {
+	if contentType == "" {
+		contentType = "unknown content type"
+	}
+	builder := strings.Builder{}
+	fmt.Fprintf(&builder, "[::b]Binary body[::-]: %s, %s (save it with the Save Body command)\n\n", formatSize(len(body)), tview.Escape(contentType))
+	if pretty.IsImage(contentType) {
+		if img, format, err := image.Decode(bytes.NewReader(body)); err == nil {
+			bounds := img.Bounds()
+			fmt.Fprintf(&builder, "%s image, %dx%d pixels\n\n", format, bounds.Dx(), bounds.Dy())
+			builder.WriteString(renderImage(img, imagePreviewWidth))
+			return builder.String()
+		}
+	}
+	builder.WriteString(tview.Escape(pretty.HexDump(body, hexDumpLimit)))
+	return builder.String()
+}
+
+// renderImage draws the image with Unicode upper half blocks, each cell
+// showing two pixels: the top one in the foreground and the bottom one in
+// the background color.
+func renderImage(img image.Image, maxWidth int) string {
+	bounds := img.Bounds()
+	if bounds.Dx() == 0 || bounds.Dy() == 0 {
+		return ""
+	}
+	width := min(bounds.Dx(), maxWidth)
+	height := max(bounds.Dy()*width/bounds.Dx(), 1)
+	pixel := func(x, y int) string {
+		if y >= height {
+			return "#000000"
+		}
+		r, g, b, _ := img.At(bounds.Min.X+x*bounds.Dx()/width, bounds.Min.Y+y*bounds.Dy()/height).RGBA()
+		return fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, b>>8)
+	}
+	builder := strings.Builder{}
+	for y := 0; y < height; y += 2 {
+		for x := 0; x < width; x++ {
+			fmt.Fprintf(&builder, "[%s:%s]▀", pixel(x, y), pixel(x, y+1))
+		}
+		builder.WriteString("[-:-]\n")
+	}
+	return builder.String()
+}
@@ -0,0 +1,147 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/go-rq/req/internal/client"
+	"github.com/rivo/tview"
+)
+
+const cookiesHelp = "Enter/e: Edit, d: Delete, Esc: Back"
+
+type CookiesView struct {
+	app          *tview.Application
+	context      context.Context
+	jar          *client.Jar
+	list         *tview.List
+	helpInfo     *tview.TextView
+	layout       *tview.Frame
+	previousView View
+	cookies      []client.Cookie
+}
+
+func NewCookiesView(ctx context.Context, app *tview.Application, previousView View) *CookiesView {
+	view := &CookiesView{
+		app:          app,
+		context:      ctx,
+		jar:          client.GetClient(ctx).Jar(),
+		list:         tview.NewList(),
+		helpInfo:     tview.NewTextView(),
+		previousView: previousView,
+	}
+	view.list.SetBorder(true).SetTitle("Cookies")
+	view.list.SetWrapAround(false)
+	view.list.SetSelectedFunc(func(idx int, _, _ string, _ rune) {
+		view.edit(idx)
+	})
+	view.helpInfo.SetTextAlign(tview.AlignCenter)
+	grid := tview.NewGrid().SetRows(0, 2)
+	grid.AddItem(view.list, 0, 0, 1, 1, 0, 0, true)
+	grid.AddItem(view.helpInfo, 1, 0, 1, 1, 0, 0, false)
+	view.layout = tview.NewFrame(grid)
+	view.layout.AddText("Cookies", true, tview.AlignCenter, tcell.ColorForestGreen)
+	view.list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEsc:
+			view.previousView.Mount(view.app)
+			return nil
+		case event.Key() == tcell.KeyRune && event.Rune() == 'd':
+			view.delete(view.list.GetCurrentItem())
+			return nil
+		case event.Key() == tcell.KeyRune && event.Rune() == 'e':
+			view.edit(view.list.GetCurrentItem())
+			return nil
+		}
+		return event
+	})
+	return view
+}
+
+func (view *CookiesView) renderList() {
+	current := view.list.GetCurrentItem()
+	view.cookies = view.jar.All()
+	view.list.Clear()
+	domain := ""
+	for _, cookie := range view.cookies {
+		// only label the first cookie of each domain so the list reads as
+		// groups of cookies per domain
+		label := ""
+		if cookie.Domain != domain {
+			label = cookie.Domain
+			domain = cookie.Domain
+		}
+		view.list.AddItem(
+			fmt.Sprintf("%-30s %s=%s", tview.Escape(label), tview.Escape(cookie.Name), tview.Escape(cookie.Value)),
+			fmt.Sprintf("%-30s %s", "", tview.Escape(cookieAttributes(cookie))),
+			0, nil)
+	}
+	if current < len(view.cookies) {
+		view.list.SetCurrentItem(current)
+	}
+}
+
+func (view *CookiesView) delete(idx int) {
+	if idx < 0 || idx >= len(view.cookies) {
+		return
+	}
+	if err := view.jar.Delete(view.cookies[idx]); err != nil {
+		view.showError(err)
+	}
+	view.renderList()
+}
+
+func (view *CookiesView) edit(idx int) {
+	if idx < 0 || idx >= len(view.cookies) {
+		return
+	}
+	cookie := view.cookies[idx]
+	ev := NewTextEditorView(
+		view.context,
+		view.app,
+		func() { view.Mount(view.app) },
+		func(update string) {
+			if err := view.jar.Replace(cookie, strings.TrimSpace(update)); err != nil {
+				view.Mount(view.app)
+				view.showError(err)
+				return
+			}
+			view.Mount(view.app)
+		},
+		"Edit Cookie",
+		cookie.String())
+	ev.Mount(view.app)
+}
+
+func (view *CookiesView) showError(err error) {
+	view.helpInfo.SetTextColor(tcell.ColorOrangeRed)
+	view.helpInfo.SetText(err.Error())
+}
+
+func cookieAttributes(cookie client.Cookie) string {
+	parts := []string{"Path=" + cookie.Path}
+	if cookie.Expires.IsZero() {
+		parts = append(parts, "Session")
+	} else {
+		parts = append(parts, "Expires="+cookie.Expires.Local().Format("2006-01-02 15:04:05"))
+	}
+	if cookie.HostOnly {
+		parts = append(parts, "HostOnly")
+	}
+	if cookie.Secure {
+		parts = append(parts, "Secure")
+	}
+	if cookie.HttpOnly {
+		parts = append(parts, "HttpOnly")
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (view *CookiesView) Mount(app *tview.Application) {
+	view.helpInfo.SetTextColor(tcell.ColorDefault)
+	view.helpInfo.SetText(cookiesHelp)
+	view.renderList()
+	app.SetRoot(view.layout, true)
+}
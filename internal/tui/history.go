@@ -0,0 +1,156 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"github.com/go-rq/req/internal/client"
+	"github.com/go-rq/req/internal/httpfile"
+	"github.com/go-rq/req/internal/template"
+	"github.com/rivo/tview"
+)
+
+type historyContextKey struct{}
+
+// History keeps the responses and response filter of every request opened
+// in the session, so they are still there when a request is opened again.
+type History struct {
+	mu       sync.Mutex
+	requests map[string]*requestHistory
+}
+
+type requestHistory struct {
+	responses []Response
+
+	// filter is the jq or JSONPath expression applied to the response body
+	filter string
+
+	// latest is the body and headers of the last response, which references
+	// to the request are resolved against from the goroutines sending
+	// requests. Unlike responses it is guarded by mu.
+	mu     sync.Mutex
+	latest *template.Response
+}
+
+// add appends the response to the history. Like every access to the
+// responses it must happen on the UI goroutine.
+func (h *requestHistory) add(resp Response) {
+	h.responses = append(h.responses, resp)
+	body, err := h.responses[len(h.responses)-1].body()
+	if err != nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.latest = &template.Response{Header: resp.Header, Body: body}
+}
+
+func (h *requestHistory) latestResponse() *template.Response {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.latest
+}
+
+func NewHistory() *History {
+	return &History{requests: map[string]*requestHistory{}}
+}
+
+// WithHistory returns a new context with the given history.
+func WithHistory(ctx context.Context, history *History) context.Context {
+	return context.WithValue(ctx, historyContextKey{}, history)
+}
+
+func getHistory(ctx context.Context) *History {
+	if history, ok := ctx.Value(historyContextKey{}).(*History); ok {
+		return history
+	}
+	return NewHistory()
+}
+
+func (h *History) get(request httpfile.Request) *requestHistory {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	key := filepath.Join(request.Dir, request.DisplayName())
+	if request.File != "" {
+		key = filepath.Join(request.File, request.DisplayName())
+	}
+	if _, ok := h.requests[key]; !ok {
+		h.requests[key] = &requestHistory{}
+	}
+	return h.requests[key]
+}
+
+// lookup returns a template.Lookup resolving response references against
+// the history. Requests sent for a reference are added to their history on
+// the UI goroutine of app.
+func (h *History) lookup(app *tview.Application) template.Lookup {
+	return func(ctx context.Context, file, name string, send bool) (*template.Response, error) {
+		requests, err := httpfile.ParseFile(file)
+		if err != nil {
+			return nil, err
+		}
+		idx := slices.IndexFunc(requests, func(request httpfile.Request) bool {
+			return request.DisplayName() == name
+		})
+		if idx < 0 {
+			return nil, fmt.Errorf("no request named %q in %s", name, file)
+		}
+		request := requests[idx]
+		history := h.get(request)
+		if latest := history.latestResponse(); latest != nil {
+			return latest, nil
+		}
+		if !send {
+			return nil, template.ErrNoResponse
+		}
+		result, err := client.GetClient(ctx).Send(ctx, &request)
+		if err != nil {
+			return nil, err
+		}
+		resp := Response{Response: *result.Response, attempts: result.Attempts, variables: result.Variables}
+		body, err := resp.body()
+		if err != nil {
+			return nil, err
+		}
+		app.QueueUpdate(func() {
+			history.add(resp)
+		})
+		return &template.Response{Header: resp.Header, Body: body}, nil
+	}
+}
+
+// recordPrerequisites adds the prerequisites run before the request to its
+// logs, followed by their own logs, and their responses to the history of
+// their request.
+func recordPrerequisites(ctx context.Context, request *httpfile.Request, prerequisites []client.Prerequisite) {
+	history := getHistory(ctx)
+	for _, prerequisite := range prerequisites {
+		request.Logs = append(request.Logs, tview.Escape(prerequisite.String()))
+		if prerequisite.Cached {
+			continue
+		}
+		for _, log := range prerequisite.Request.Logs {
+			request.Logs = append(request.Logs, "  "+log)
+		}
+		if result := prerequisite.Result; result != nil && result.Response != nil {
+			history.get(prerequisite.Request).add(Response{Response: *result.Response, attempts: result.Attempts, variables: result.Variables})
+		}
+	}
+}
+
+// recordPolls adds the polls of the request to its logs and their responses
+// to its history, except the last one if the send succeeded, which is added
+// like any other response.
+func recordPolls(ctx context.Context, request *httpfile.Request, polls []client.Poll, succeeded bool) {
+	history := getHistory(ctx).get(*request)
+	for i, poll := range polls {
+		request.Logs = append(request.Logs, tview.Escape(poll.String()))
+		if succeeded && i == len(polls)-1 {
+			continue
+		}
+		history.add(Response{Response: *poll.Result.Response, attempts: poll.Result.Attempts, variables: poll.Result.Variables})
+	}
+}
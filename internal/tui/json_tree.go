@@ -0,0 +1,236 @@
+package tui
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+
+	"github.com/atotto/clipboard"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const jsonTreeHelp = "Enter: Expand/Collapse, p: Copy Path, y: Copy Value, Esc: Back"
+
+var identifierRegexp = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// jsonNode is a decoded JSON value. Object keys keep the order they have in
+// the document, which a map would lose.
+type jsonNode struct {
+	path     string
+	key      string
+	value    any
+	object   bool
+	array    bool
+	children []*jsonNode
+}
+
+// JSONTreeView shows a JSON document as a tree of collapsible objects and
+// arrays. Tree nodes are only created when their parent is expanded so large
+// documents stay responsive.
+type JSONTreeView struct {
+	app          *tview.Application
+	tree         *tview.TreeView
+	helpInfo     *tview.TextView
+	layout       *tview.Frame
+	previousView View
+}
+
+func NewJSONTreeView(app *tview.Application, title string, body []byte, previousView View) (*JSONTreeView, error) {
+	root, err := decodeJSON(body)
+	if err != nil {
+		return nil, fmt.Errorf("response body is not valid JSON: %w", err)
+	}
+	view := &JSONTreeView{
+		app:          app,
+		tree:         tview.NewTreeView(),
+		helpInfo:     tview.NewTextView(),
+		previousView: previousView,
+	}
+	rootNode := newTreeNode(root)
+	expandTreeNode(rootNode)
+	view.tree.SetRoot(rootNode).SetCurrentNode(rootNode)
+	view.tree.SetBorder(true).SetTitle("JSON")
+	view.tree.SetSelectedFunc(func(node *tview.TreeNode) {
+		if !node.IsExpanded() || len(node.GetChildren()) == 0 {
+			expandTreeNode(node)
+			return
+		}
+		node.Collapse()
+	})
+	view.helpInfo.SetTextAlign(tview.AlignCenter)
+	grid := tview.NewGrid().SetRows(0, 2)
+	grid.AddItem(view.tree, 0, 0, 1, 1, 0, 0, true)
+	grid.AddItem(view.helpInfo, 1, 0, 1, 1, 0, 0, false)
+	view.layout = tview.NewFrame(grid)
+	view.layout.AddText(tview.Escape(title), true, tview.AlignCenter, tcell.ColorForestGreen)
+	view.tree.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		node, _ := view.tree.GetCurrentNode().GetReference().(*jsonNode)
+		switch {
+		case event.Key() == tcell.KeyEsc:
+			view.previousView.Mount(view.app)
+			return nil
+		case event.Key() == tcell.KeyRune && event.Rune() == 'p' && node != nil:
+			view.copy("path", node.path)
+			return nil
+		case event.Key() == tcell.KeyRune && event.Rune() == 'y' && node != nil:
+			view.copy("value", node.text())
+			return nil
+		}
+		return event
+	})
+	return view, nil
+}
+
+func (view *JSONTreeView) copy(what, text string) {
+	if err := clipboard.WriteAll(text); err != nil {
+		view.helpInfo.SetTextColor(tcell.ColorOrangeRed).SetText(err.Error())
+		return
+	}
+	view.helpInfo.SetTextColor(tcell.ColorDefault).SetText(fmt.Sprintf("copied %s to clipboard", what))
+}
+
+func (view *JSONTreeView) Mount(app *tview.Application) {
+	view.helpInfo.SetTextColor(tcell.ColorDefault).SetText(jsonTreeHelp)
+	app.SetRoot(view.layout, true)
+}
+
+func newTreeNode(node *jsonNode) *tview.TreeNode {
+	treeNode := tview.NewTreeNode(node.label()).SetReference(node).SetSelectable(true)
+	if node.object || node.array {
+		treeNode.SetColor(tcell.ColorTeal)
+	}
+	return treeNode.SetExpanded(false)
+}
+
+// expandTreeNode creates the children of the tree node the first time it is
+// expanded.
+func expandTreeNode(treeNode *tview.TreeNode) {
+	node := treeNode.GetReference().(*jsonNode)
+	if len(treeNode.GetChildren()) == 0 {
+		for _, child := range node.children {
+			treeNode.AddChild(newTreeNode(child))
+		}
+	}
+	treeNode.SetExpanded(true)
+}
+
+func (n *jsonNode) label() string {
+	prefix := ""
+	if n.key != "" {
+		prefix = tview.Escape(n.key) + ": "
+	}
+	switch {
+	case n.object:
+		return fmt.Sprintf("%s{…} (%d keys)", prefix, len(n.children))
+	case n.array:
+		return fmt.Sprintf("%s[…] (%d items)", prefix, len(n.children))
+	}
+	value, _ := json.Marshal(n.value)
+	return prefix + tview.Escape(string(value))
+}
+
+// text returns the JSON encoding of the node, except for strings which are
+// returned as is.
+func (n *jsonNode) text() string {
+	if s, ok := n.value.(string); ok && !n.object && !n.array {
+		return s
+	}
+	buf := &bytes.Buffer{}
+	n.encode(buf, "")
+	return buf.String()
+}
+
+func (n *jsonNode) encode(buf *bytes.Buffer, indent string) {
+	switch {
+	case n.object, n.array:
+		open, end := "[", "]"
+		if n.object {
+			open, end = "{", "}"
+		}
+		if len(n.children) == 0 {
+			buf.WriteString(open + end)
+			return
+		}
+		buf.WriteString(open + "\n")
+		for i, child := range n.children {
+			buf.WriteString(indent + "  ")
+			if n.object {
+				key, _ := json.Marshal(child.key)
+				buf.Write(key)
+				buf.WriteString(": ")
+			}
+			child.encode(buf, indent+"  ")
+			if i < len(n.children)-1 {
+				buf.WriteString(",")
+			}
+			buf.WriteString("\n")
+		}
+		buf.WriteString(indent + end)
+	default:
+		value, _ := json.Marshal(n.value)
+		buf.Write(value)
+	}
+}
+
+func decodeJSON(body []byte) (*jsonNode, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	root, err := decodeJSONNode(decoder, "$", "")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("unexpected data after the top-level value")
+	}
+	return root, nil
+}
+
+func decodeJSONNode(decoder *json.Decoder, path, key string) (*jsonNode, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	node := &jsonNode{path: path, key: key}
+	switch token {
+	case json.Delim('{'):
+		node.object = true
+		for decoder.More() {
+			token, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			childKey := token.(string)
+			child, err := decodeJSONNode(decoder, objectPath(path, childKey), childKey)
+			if err != nil {
+				return nil, err
+			}
+			node.children = append(node.children, child)
+		}
+		_, err = decoder.Token()
+	case json.Delim('['):
+		node.array = true
+		for i := 0; decoder.More(); i++ {
+			child, err := decodeJSONNode(decoder, path+"["+strconv.Itoa(i)+"]", "["+strconv.Itoa(i)+"]")
+			if err != nil {
+				return nil, err
+			}
+			node.children = append(node.children, child)
+		}
+		_, err = decoder.Token()
+	default:
+		node.value = token
+	}
+	return node, err
+}
+
+func objectPath(path, key string) string {
+	if identifierRegexp.MatchString(key) {
+		return path + "." + key
+	}
+	quoted, _ := json.Marshal(key)
+	return path + "[" + string(quoted) + "]"
+}
@@ -1,8 +1,11 @@
 package tui
 
 import (
+	"slices"
+	"strings"
+
 	"github.com/gdamore/tcell/v2"
-	"github.com/go-rq/rq"
+	"github.com/go-rq/req/internal/httpfile"
 	"github.com/rivo/tview"
 	"github.com/sahilm/fuzzy"
 	"github.com/samber/lo"
@@ -15,15 +18,19 @@ type RequestSelect struct {
 	layout           *tview.Frame
 	inputField       *tview.InputField
 	selectedCallback RequestSelectedCallback
+	runAllCallback   RunAllCallback
 	path             string
 	searchString     string
-	selected         rq.Request
-	requests         []rq.Request
+	selected         httpfile.Request
+	requests         []httpfile.Request
 }
 
-type RequestSelectedCallback func(request rq.Request)
+type RequestSelectedCallback func(request httpfile.Request)
+
+// RunAllCallback is called with the requests of the file to run them all.
+type RunAllCallback func(requests []httpfile.Request)
 
-type requestFuzzySource []rq.Request
+type requestFuzzySource []httpfile.Request
 
 func (r requestFuzzySource) String(i int) string {
 	return r[i].DisplayName()
@@ -55,21 +62,23 @@ func NewRequestSelectView(app *tview.Application, path string, previousView View
 	view.loadRequests()
 	view.layout = tview.NewFrame(flex)
 	view.layout.AddText("Select Request", true, tview.AlignCenter, tcell.ColorBlue)
+	view.layout.AddText("Ctrl-R: Run all", false, tview.AlignCenter, tcell.ColorGray)
 	view.layout.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
 		switch event.Key() {
 		case tcell.KeyEsc:
 			view.previousView.Mount(view.app)
+		case tcell.KeyCtrlR:
+			if view.runAllCallback != nil {
+				requests := runAllRequests(view.inputField.GetText(), view.requests)
+				view.clear()
+				view.runAllCallback(requests)
+			}
+			return nil
 		case tcell.KeyUp, tcell.KeyDown, tcell.KeyPgUp, tcell.KeyPgDn, tcell.KeyHome, tcell.KeyEnd, tcell.KeyEnter:
 			view.list.InputHandler()(event, nil)
 		default:
 			view.inputField.InputHandler()(event, nil)
-			text := view.inputField.GetText()
-			requests := view.requests
-			if text != "" {
-				matches := fuzzy.FindFrom(view.inputField.GetText(), requestFuzzySource(requests))
-				requests = lo.Map(matches, func(m fuzzy.Match, _ int) rq.Request { return requests[m.Index] })
-			}
-			view.renderList(requests)
+			view.renderList(filterRequests(view.inputField.GetText(), view.requests))
 		}
 		return event
 	})
@@ -77,11 +86,66 @@ func NewRequestSelectView(app *tview.Application, path string, previousView View
 	return view
 }
 
+// filterRequests returns the requests matching the filter text, the best
+// matches of the name first.
+func filterRequests(text string, requests []httpfile.Request) []httpfile.Request {
+	return lo.Map(filterIndices(text, requests), func(i int, _ int) httpfile.Request { return requests[i] })
+}
+
+// runAllRequests returns the requests matching the filter text in the order
+// of the file, as scripts may set variables for the requests that follow.
+func runAllRequests(text string, requests []httpfile.Request) []httpfile.Request {
+	indices := filterIndices(text, requests)
+	slices.Sort(indices)
+	return lo.Map(indices, func(i int, _ int) httpfile.Request { return requests[i] })
+}
+
+// filterIndices returns the indices of the requests matching the filter
+// text. Tokens of the form `tag:<tag>` and `method:<method>` select the
+// requests with a tag or method starting with the value, ignoring case, and
+// the rest of the text is matched fuzzily against the names of the requests,
+// ranking the best matches first.
+func filterIndices(text string, requests []httpfile.Request) []int {
+	indices := lo.Range(len(requests))
+	var words []string
+	for _, token := range strings.Fields(text) {
+		key, value, _ := strings.Cut(token, ":")
+		switch strings.ToLower(key) {
+		case "tag":
+			indices = lo.Filter(indices, func(i int, _ int) bool {
+				return lo.SomeBy(requests[i].Tags(), func(tag string) bool {
+					return hasPrefixFold(tag, value)
+				})
+			})
+		case "method":
+			indices = lo.Filter(indices, func(i int, _ int) bool {
+				return hasPrefixFold(requests[i].Method, value)
+			})
+		default:
+			words = append(words, token)
+		}
+	}
+	if len(words) == 0 {
+		return indices
+	}
+	source := requestFuzzySource(lo.Map(indices, func(i int, _ int) httpfile.Request { return requests[i] }))
+	matches := fuzzy.FindFrom(strings.Join(words, " "), source)
+	return lo.Map(matches, func(m fuzzy.Match, _ int) int { return indices[m.Index] })
+}
+
+func hasPrefixFold(s, prefix string) bool {
+	return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+}
+
 func (f *RequestSelect) SetCallback(callback RequestSelectedCallback) {
 	f.selectedCallback = callback
 }
 
-func (f *RequestSelect) selectRequest(request rq.Request) func() {
+func (f *RequestSelect) SetRunAllCallback(callback RunAllCallback) {
+	f.runAllCallback = callback
+}
+
+func (f *RequestSelect) selectRequest(request httpfile.Request) func() {
 	return func() {
 		if f.selectedCallback != nil {
 			f.clear()
@@ -95,7 +159,7 @@ func (f *RequestSelect) clear() {
 	f.renderList(f.requests)
 }
 
-func (f *RequestSelect) renderList(requests []rq.Request) {
+func (f *RequestSelect) renderList(requests []httpfile.Request) {
 	f.list.Clear()
 	for _, value := range requests {
 		f.list.AddItem(value.DisplayName(), "", 0, f.selectRequest(value))
@@ -103,7 +167,7 @@ func (f *RequestSelect) renderList(requests []rq.Request) {
 }
 
 func (f *RequestSelect) loadRequests() error {
-	requests, err := rq.ParseFromFile(f.path)
+	requests, err := httpfile.ParseFile(f.path)
 	if err != nil {
 		return err
 	}
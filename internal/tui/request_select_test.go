@@ -0,0 +1,94 @@
+package tui
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/go-rq/req/internal/httpfile"
+	"github.com/go-rq/rq"
+	"github.com/samber/lo"
+)
+
+func testRequest(name, method string, tags ...string) httpfile.Request {
+	request := httpfile.Request{Request: rq.Request{Name: name, Method: method}}
+	for _, tag := range tags {
+		request.Directives = append(request.Directives, httpfile.Directive{Name: "tag", Value: tag})
+	}
+	return request
+}
+
+var testRequests = []httpfile.Request{
+	testRequest("Login", "POST", "auth"),
+	testRequest("Create Order For User", "POST", "orders"),
+	testRequest("List Orders", "GET", "orders", "smoke"),
+	testRequest("Order", "GET", "orders"),
+	testRequest("Logout", "DELETE", "auth"),
+}
+
+func names(requests []httpfile.Request) []string {
+	return lo.Map(requests, func(request httpfile.Request, _ int) string { return request.Name })
+}
+
+func TestFilterRequests(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"", []string{"Login", "Create Order For User", "List Orders", "Order", "Logout"}},
+		{"tag:auth", []string{"Login", "Logout"}},
+		{"TAG:Ord", []string{"Create Order For User", "List Orders", "Order"}},
+		{"tag:orders tag:smoke", []string{"List Orders"}},
+		{"method:get", []string{"List Orders", "Order"}},
+		{"method:P tag:auth", []string{"Login"}},
+		{"tag:none", nil},
+		{"logout", []string{"Logout"}},
+		{"method:delete login", nil},
+		{"order", []string{"Order", "List Orders", "Create Order For User"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got := names(filterRequests(tt.text, testRequests))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("filterRequests(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunAllRequestsKeepsFileOrder(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"", []string{"Login", "Create Order For User", "List Orders", "Order", "Logout"}},
+		{"order", []string{"Create Order For User", "List Orders", "Order"}},
+		{"method:get order", []string{"List Orders", "Order"}},
+		{"tag:auth lo", []string{"Login", "Logout"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got := names(runAllRequests(tt.text, testRequests))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("runAllRequests(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHasPrefixFold(t *testing.T) {
+	tests := []struct {
+		s, prefix string
+		want      bool
+	}{
+		{"orders", "ord", true},
+		{"Orders", "oRD", true},
+		{"orders", "", true},
+		{"ord", "orders", false},
+		{"smoke", "ord", false},
+	}
+	for _, tt := range tests {
+		if got := hasPrefixFold(tt.s, tt.prefix); got != tt.want {
+			t.Errorf("hasPrefixFold(%q, %q) = %t, want %t", tt.s, tt.prefix, got, tt.want)
+		}
+	}
+}
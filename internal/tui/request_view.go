@@ -3,13 +3,21 @@ package tui
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/alecthomas/chroma/quick"
 	"github.com/atotto/clipboard"
 	"github.com/gdamore/tcell/v2"
+	"github.com/go-rq/req/internal/client"
+	"github.com/go-rq/req/internal/httpfile"
+	"github.com/go-rq/req/internal/pretty"
+	"github.com/go-rq/req/internal/template"
 	"github.com/go-rq/rq"
 	"github.com/rivo/tview"
 )
@@ -22,11 +30,13 @@ const (
 	ColorScheme      = "terminal16"
 )
 
-var SelectedRequest *rq.Request
+var spinnerFrames = []rune("⠋⠙⠹⠸⠼⠴⠦⠧⠇⠏")
+
+var SelectedRequest *httpfile.Request
 
 type RequestView struct {
 	app            *tview.Application
-	request        *rq.Request
+	request        *httpfile.Request
 	layout         *tview.Flex
 	frame          *tview.Frame
 	main           *tview.TextView
@@ -34,38 +44,19 @@ type RequestView struct {
 	context        context.Context
 	previousView   View
 	refreshContent func()
+	cancelSend     context.CancelFunc
+	summary        string
+	status         string
 	commands       []Command
-	responses      []Response
+	history        *requestHistory
+	search         search
+	prompt         *tview.InputField
 	baseCommands   []Command
 }
 
-type Response struct {
-	cachedPrettyString string
-	cachedRawString    string
-	rq.Response
-}
-
-func (p *Response) prettyString() (string, error) {
-	if p.cachedPrettyString == "" {
-		text, err := p.Response.PrettyString()
-		if err != nil {
-			return "", err
-		}
-		p.cachedPrettyString = colorize(text, HTTPLexer, DefaultTheme, true)
-	}
-	return p.cachedPrettyString, nil
-}
-
-func (p *Response) rawString() string {
-	if p.cachedRawString == "" {
-		p.cachedRawString = colorize(p.Response.String(), HTTPLexer, DefaultTheme, true)
-	}
-
-	return p.cachedRawString
-}
-
-func NewRequestView(ctx context.Context, app *tview.Application, request rq.Request, previousView View) *RequestView {
+func NewRequestView(ctx context.Context, app *tview.Application, request httpfile.Request, previousView View) *RequestView {
 	flex := tview.NewFlex().SetDirection(tview.FlexRow)
+	ctx = template.WithLookup(ctx, getHistory(ctx).lookup(app))
 	view := &RequestView{
 		app:          app,
 		context:      ctx,
@@ -75,6 +66,8 @@ func NewRequestView(ctx context.Context, app *tview.Application, request rq.Requ
 		commandsView: tview.NewTextView(),
 		layout:       flex,
 		previousView: previousView,
+		history:      getHistory(ctx).get(request),
+		prompt:       tview.NewInputField(),
 	}
 	view.baseCommands = []Command{
 		{
@@ -110,6 +103,57 @@ func NewRequestView(ctx context.Context, app *tview.Application, request rq.Requ
 				ev.Mount(app)
 			},
 		},
+		{
+			Name: "Search",
+			Key:  tcell.KeyRune,
+			Rune: '/',
+			Handler: func() {
+				view.editSearch()
+			},
+		},
+		{
+			Key:  tcell.KeyRune,
+			Rune: 'n',
+			Handler: func() {
+				view.nextMatch(1)
+			},
+		},
+		{
+			Key:  tcell.KeyRune,
+			Rune: 'N',
+			Handler: func() {
+				view.nextMatch(-1)
+			},
+		},
+		{
+			Name: "Options",
+			Key:  tcell.KeyRune,
+			Rune: 'o',
+			Handler: func() {
+				ev := NewTextEditorView(
+					ctx,
+					app,
+					func() { view.Mount(app) },
+					func(update string) {
+						if err := setSettings(ctx, update); err != nil {
+							view.showError(err)
+							return
+						}
+						view.Mount(app)
+					},
+					"Options",
+					client.GetClient(ctx).Settings.String())
+				ev.Mount(app)
+			},
+		},
+		{
+			Name: "Cookies",
+			Key:  tcell.KeyRune,
+			Rune: 'k',
+			Handler: func() {
+				NewCookiesView(ctx, app, view).Mount(app)
+			},
+		},
 		{
 			Name: "Edit",
 			Key:  tcell.KeyRune,
@@ -120,7 +164,7 @@ func NewRequestView(ctx context.Context, app *tview.Application, request rq.Requ
 					app,
 					func() { view.Mount(app) },
 					func(update string) {
-						reqs, err := rq.ParseRequests(update)
+						reqs, err := httpfile.Parse(view.request.Dir, update)
 						if err != nil {
 							view.showError(err)
 							return
@@ -129,6 +173,7 @@ func NewRequestView(ctx context.Context, app *tview.Application, request rq.Requ
 							view.showError(fmt.Errorf("unable to edit multiple requests at once"))
 							return
 						}
+						reqs[0].File = view.request.File
 						view.request = &reqs[0]
 						view.Mount(app)
 					},
@@ -139,7 +184,7 @@ func NewRequestView(ctx context.Context, app *tview.Application, request rq.Requ
 		},
 	}
 
-	view.frame.AddText(request.DisplayName(), true, tview.AlignCenter, tcell.ColorForestGreen)
+	view.setStatus("")
 	view.layout.AddItem(view.main, 0, 7, true).
 		AddItem(view.commandsView, 1, 0, false)
 	view.showRawRequest()
@@ -174,12 +219,13 @@ func colorize(text, lexer, theme string, color bool) string {
 }
 
 func (view *RequestView) showRawRequest() {
+	view.setSummary(-1)
 	view.main.SetBorder(false).SetTitle("Request").SetTitleColor(tcell.ColorAliceBlue)
 	view.main.SetTextColor(tcell.ColorDefault)
 	view.refreshContent = func() {
 		view.main.SetText(colorize(view.request.HttpText(), HTTPLexer, AlternativeTheme, true))
 	}
-	view.refreshContent()
+	view.refresh()
 	commands := []Command{
 		{
 			Name: "Back",
@@ -207,13 +253,7 @@ func (view *RequestView) showRawRequest() {
 			Name: "Send",
 			Key:  tcell.KeyEnter,
 			Handler: func() {
-				resp, err := view.request.Do(view.context)
-				if err != nil {
-					view.showError(err)
-					return
-				}
-				view.responses = append(view.responses, Response{Response: *resp})
-				view.showPrettyResponse(len(view.responses) - 1)
+				view.send()
 			},
 		},
 		{
@@ -225,17 +265,32 @@ func (view *RequestView) showRawRequest() {
 			},
 		},
 	}
+	if count := len(view.history.responses); count > 0 {
+		commands = append(commands, Command{
+			Name: "Last Response",
+			Key:  tcell.KeyRune,
+			Rune: 'p',
+			Handler: func() {
+				view.showPrettyResponse(count - 1)
+			},
+		})
+	}
 	view.registerCommands(append(view.baseCommands, commands...)...)
 }
 
 func (view *RequestView) showProcessedRequest() {
+	view.setSummary(-1)
 	view.main.SetBorder(false).SetTitle("Request").SetTitleColor(tcell.ColorAliceBlue)
 	view.main.SetTextColor(tcell.ColorDefault)
 	view.refreshContent = func() {
-		request := view.request.ApplyEnv(view.context)
+		request, err := view.processedRequest()
+		if err != nil {
+			view.showError(err)
+			return
+		}
 		view.main.SetText(colorize(request.HttpText(), HTTPLexer, "doom-one", true))
 	}
-	view.refreshContent()
+	view.refresh()
 	commands := []Command{
 		{
 			Name: "Back",
@@ -263,13 +318,7 @@ func (view *RequestView) showProcessedRequest() {
 			Name: "Send",
 			Key:  tcell.KeyEnter,
 			Handler: func() {
-				resp, err := view.request.Do(view.context)
-				if err != nil {
-					view.showError(err)
-					return
-				}
-				view.responses = append(view.responses, Response{Response: *resp})
-				view.showPrettyResponse(len(view.responses) - 1)
+				view.send()
 			},
 		},
 		{
@@ -281,22 +330,80 @@ func (view *RequestView) showProcessedRequest() {
 			},
 		},
 	}
+	if count := len(view.history.responses); count > 0 {
+		commands = append(commands, Command{
+			Name: "Last Response",
+			Key:  tcell.KeyRune,
+			Rune: 'p',
+			Handler: func() {
+				view.showPrettyResponse(count - 1)
+			},
+		})
+	}
 	view.registerCommands(append(view.baseCommands, commands...)...)
 }
 
+// processedRequest returns the request with its template expressions
+// resolved, the environment applied and the body encoded as it is sent.
+// Multipart bodies are summarized part by part and other binary bodies by
+// their size. Referenced requests are not sent for the preview.
+func (view *RequestView) processedRequest() (rq.Request, error) {
+	env := rq.GetEnvironment(view.context)
+	request, err := template.New(view.context, view.request).Request(view.request.Request)
+	if err != nil {
+		return request, err
+	}
+	request = request.ApplyEnv(view.context)
+	contentType := -1
+	for i, header := range request.Headers {
+		if strings.EqualFold(header.Key, "Content-Type") {
+			contentType = i
+		}
+	}
+	var body []byte
+	if contentType < 0 {
+		body, err = view.request.InlineBody([]byte(request.Body), env)
+	} else {
+		header := &request.Headers[contentType]
+		body, header.Value, err = view.request.EncodeBody(header.Value, []byte(request.Body), env)
+	}
+	if err != nil {
+		return request, err
+	}
+	switch {
+	case contentType >= 0 && strings.HasPrefix(strings.ToLower(request.Headers[contentType].Value), "multipart/"):
+		request.Body, _ = pretty.Format(request.Headers[contentType].Value, body)
+		request.Body += "\n"
+	case pretty.IsBinary("", body):
+		request.Body = fmt.Sprintf("<binary body of %s>\n", formatSize(len(body)))
+	default:
+		request.Body = string(body)
+	}
+	return request, nil
+}
+
 func (view *RequestView) showPrettyResponse(idx int) {
+	view.setSummary(idx)
 	view.main.SetBorder(false).SetTitle("Response (Pretty)").SetTitleColor(tcell.ColorLawnGreen)
 	view.main.SetTextColor(tcell.ColorDefault)
 	view.refreshContent = func() {
-		resp := &view.responses[idx]
+		resp := &view.history.responses[idx]
+		if filter := view.history.filter; filter != "" {
+			text, err := resp.filtered(filter)
+			if err != nil {
+				text = fmt.Sprintf("[red]%s[-]", tview.Escape(err.Error()))
+			}
+			view.main.SetText(fmt.Sprintf("[::d]filter: %s[::-]\n\n%s", tview.Escape(filter), text))
+			return
+		}
 		text, err := resp.prettyString()
 		if err != nil {
 			view.showError(err)
 			return
 		}
-		view.main.SetText(text)
+		view.main.SetText(resp.variablesSection() + resp.redirectChain() + text)
 	}
-	view.refreshContent()
+	view.refresh()
 	commands := []Command{
 		{
 			Name: "Clear",
@@ -321,6 +428,38 @@ func (view *RequestView) showPrettyResponse(idx int) {
 				view.showAssertions(idx, view.showPrettyResponse)
 			},
 		},
+		{
+			Name: "Attempts",
+			Key:  tcell.KeyRune,
+			Rune: 'h',
+			Handler: func() {
+				view.showAttempts(idx, view.showPrettyResponse)
+			},
+		},
+		{
+			Name: "Timing",
+			Key:  tcell.KeyRune,
+			Rune: 't',
+			Handler: func() {
+				view.showTiming(idx, view.showPrettyResponse)
+			},
+		},
+		{
+			Name: "JSON Tree",
+			Key:  tcell.KeyRune,
+			Rune: 'j',
+			Handler: func() {
+				view.showJSONTree(idx)
+			},
+		},
+		{
+			Name: "Filter",
+			Key:  tcell.KeyRune,
+			Rune: 'f',
+			Handler: func() {
+				view.editFilter(idx)
+			},
+		},
 		{
 			Name: "Logs",
 			Key:  tcell.KeyRune,
@@ -329,19 +468,128 @@ func (view *RequestView) showPrettyResponse(idx int) {
 				view.showLogs(func() { view.showPrettyResponse(idx) })
 			},
 		},
+		{
+			Name: "Save Body",
+			Key:  tcell.KeyRune,
+			Rune: 's',
+			Handler: func() {
+				view.saveBody(idx)
+			},
+		},
 	}
+	commands = append(commands, view.historyCommands(idx, view.showPrettyResponse)...)
 	view.registerCommands(append(view.baseCommands, commands...)...)
 }
 
+// editFilter shows an input for the jq or JSONPath expression the pretty
+// response body is filtered with. The response is re-rendered as the
+// expression is typed; Enter keeps it and Esc restores the previous one.
+func (view *RequestView) editFilter(idx int) {
+	previous := view.history.filter
+	view.prompt.SetChangedFunc(nil)
+	view.prompt.SetLabel("Filter (jq or $.json.path): ").SetText(previous)
+	view.prompt.SetChangedFunc(func(text string) {
+		view.history.filter = strings.TrimSpace(text)
+		view.refresh()
+	})
+	view.prompt.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEscape {
+			view.history.filter = previous
+		}
+		view.setPromptVisible(false)
+		view.showPrettyResponse(idx)
+	})
+	view.setPromptVisible(true)
+}
+
+// saveBody shows an input for the file the body of the response is written
+// to. Relative paths are resolved against the directory of the request file.
+func (view *RequestView) saveBody(idx int) {
+	resp := &view.history.responses[idx]
+	view.prompt.SetChangedFunc(nil)
+	view.prompt.SetLabel("Save body to: ").SetText(resp.filename())
+	view.prompt.SetDoneFunc(func(key tcell.Key) {
+		view.setPromptVisible(false)
+		name := strings.TrimSpace(view.prompt.GetText())
+		if key == tcell.KeyEscape || name == "" {
+			return
+		}
+		if !filepath.IsAbs(name) {
+			name = filepath.Join(view.request.Dir, name)
+		}
+		body, err := resp.body()
+		if err == nil {
+			err = os.WriteFile(name, body, 0o644)
+		}
+		if err != nil {
+			view.setStatus(fmt.Sprintf("[red]%s[-]", tview.Escape(err.Error())))
+			return
+		}
+		view.setStatus(fmt.Sprintf("saved %s to %s", formatSize(len(body)), tview.Escape(name)))
+	})
+	view.setPromptVisible(true)
+}
+
+// refresh renders the content of the current screen and highlights the
+// matches of the active search in it.
+func (view *RequestView) refresh() {
+	view.refreshContent()
+	view.search.source = view.main.GetText(false)
+	view.search.current = 0
+	view.applySearch()
+}
+
+func (view *RequestView) setPromptVisible(visible bool) {
+	view.layout.Clear()
+	view.layout.AddItem(view.main, 0, 7, !visible)
+	if visible {
+		view.layout.AddItem(view.prompt, 1, 0, true)
+	}
+	view.layout.AddItem(view.commandsView, 1, 0, false)
+	if visible {
+		view.app.SetFocus(view.prompt)
+	} else {
+		view.app.SetFocus(view.main)
+	}
+}
+
+// historyCommands returns the commands moving between the responses received
+// for the request.
+func (view *RequestView) historyCommands(idx int, show func(int)) []Command {
+	var commands []Command
+	if idx > 0 {
+		commands = append(commands, Command{
+			Name: "Previous",
+			Key:  tcell.KeyRune,
+			Rune: '[',
+			Handler: func() {
+				show(idx - 1)
+			},
+		})
+	}
+	if idx < len(view.history.responses)-1 {
+		commands = append(commands, Command{
+			Name: "Next",
+			Key:  tcell.KeyRune,
+			Rune: ']',
+			Handler: func() {
+				show(idx + 1)
+			},
+		})
+	}
+	return commands
+}
+
 func (view *RequestView) showRawResponse(idx int) {
+	view.setSummary(idx)
 	view.main.SetBorder(false).SetTitle("Response (Raw)").SetTitleColor(tcell.ColorLawnGreen)
 	view.main.SetTextColor(tcell.ColorDefault)
 	view.refreshContent = func() {
-		resp := &view.responses[idx]
+		resp := &view.history.responses[idx]
 		text := resp.rawString()
-		view.main.SetText(text)
+		view.main.SetText(resp.variablesSection() + resp.redirectChain() + text)
 	}
-	view.refreshContent()
+	view.refresh()
 	commands := []Command{
 		{
 			Name: "Clear",
@@ -366,6 +614,30 @@ func (view *RequestView) showRawResponse(idx int) {
 				view.showAssertions(idx, view.showRawResponse)
 			},
 		},
+		{
+			Name: "Attempts",
+			Key:  tcell.KeyRune,
+			Rune: 'h',
+			Handler: func() {
+				view.showAttempts(idx, view.showRawResponse)
+			},
+		},
+		{
+			Name: "Timing",
+			Key:  tcell.KeyRune,
+			Rune: 't',
+			Handler: func() {
+				view.showTiming(idx, view.showRawResponse)
+			},
+		},
+		{
+			Name: "JSON Tree",
+			Key:  tcell.KeyRune,
+			Rune: 'j',
+			Handler: func() {
+				view.showJSONTree(idx)
+			},
+		},
 		{
 			Name: "Logs",
 			Key:  tcell.KeyRune,
@@ -374,16 +646,173 @@ func (view *RequestView) showRawResponse(idx int) {
 				view.showLogs(func() { view.showRawResponse(idx) })
 			},
 		},
+		{
+			Name: "Save Body",
+			Key:  tcell.KeyRune,
+			Rune: 's',
+			Handler: func() {
+				view.saveBody(idx)
+			},
+		},
 	}
+	commands = append(commands, view.historyCommands(idx, view.showRawResponse)...)
 	view.registerCommands(append(view.baseCommands, commands...)...)
 }
 
+// send executes the request in the background so the UI stays responsive. A
+// spinner with the elapsed time is shown in the frame until the response
+// arrives or the send is cancelled.
+func (view *RequestView) send() {
+	ctx, cancel := context.WithCancel(view.context)
+	view.cancelSend = cancel
+	request := *view.request
+	done := make(chan struct{})
+	start := time.Now()
+
+	// forward Ctrl+C to the view instead of letting tview stop the application
+	view.app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyCtrlC {
+			return tcell.NewEventKey(tcell.KeyCtrlC, 0, tcell.ModNone)
+		}
+		return event
+	})
+	commands := []Command{
+		{
+			Name: "Quit",
+			Key:  tcell.KeyCtrlQ,
+			Handler: func() {
+				view.app.Stop()
+			},
+		},
+		{
+			Name: "Cancel",
+			Key:  tcell.KeyEscape,
+			Handler: func() {
+				view.cancel()
+			},
+		},
+		{
+			Name: "Cancel",
+			Key:  tcell.KeyCtrlC,
+			Handler: func() {
+				view.cancel()
+			},
+		},
+	}
+	view.registerCommands(commands...)
+
+	// the latest poll of a request declared with @poll, only accessed on the
+	// UI goroutine
+	var polling string
+	ctx = client.WithPollFunc(ctx, func(poll client.Poll) {
+		view.app.QueueUpdate(func() {
+			polling = fmt.Sprintf(", poll #%d: %s", poll.Number, tview.Escape(poll.Result.Response.Status))
+		})
+	})
+
+	go func() {
+		ticker := time.NewTicker(100 * time.Millisecond)
+		defer ticker.Stop()
+		for i := 0; ; i++ {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				frame := spinnerFrames[i%len(spinnerFrames)]
+				elapsed := time.Since(start).Round(100 * time.Millisecond)
+				view.app.QueueUpdateDraw(func() {
+					select {
+					case <-done:
+					default:
+						view.setStatus(fmt.Sprintf("%c Sending... %s%s", frame, elapsed, polling))
+					}
+				})
+			}
+		}
+	}()
+
+	go func() {
+		result, err := client.GetClient(ctx).Send(ctx, &request)
+		view.app.QueueUpdateDraw(func() {
+			close(done)
+			cancel()
+			view.cancelSend = nil
+			view.app.SetInputCapture(nil)
+			view.setStatus("")
+			view.request = &request
+			recordPrerequisites(view.context, view.request, result.Prerequisites)
+			recordPolls(view.context, view.request, result.Polls, err == nil)
+			if errors.Is(err, context.Canceled) {
+				err = fmt.Errorf("request cancelled after %s", time.Since(start).Round(time.Millisecond))
+			}
+			if err != nil {
+				if len(result.Attempts) > 1 {
+					err = fmt.Errorf("%w\n\n%s", err, formatAttempts(result.Attempts))
+				}
+				if len(result.Polls) > 0 {
+					err = fmt.Errorf("%w\n\n%s", err, formatPolls(result.Polls))
+				}
+				view.showError(err)
+				return
+			}
+			view.history.add(Response{Response: *result.Response, attempts: result.Attempts, variables: result.Variables})
+			view.showPrettyResponse(len(view.history.responses) - 1)
+			var status []string
+			if count := len(result.Polls); count > 0 {
+				status = append(status, fmt.Sprintf("condition true at poll #%d", count))
+			}
+			if result.OutputErr != nil {
+				status = append(status, fmt.Sprintf("[red]saving body: %s[-]", tview.Escape(result.OutputErr.Error())))
+			} else if result.Output != "" {
+				status = append(status, fmt.Sprintf("saved body to %s", tview.Escape(result.Output)))
+			}
+			view.setStatus(strings.Join(status, ", "))
+		})
+	}()
+}
+
+// cancel aborts an in-flight send. The pending request observes the
+// cancelled context and reports the cancellation through showError.
+func (view *RequestView) cancel() {
+	if view.cancelSend != nil {
+		view.cancelSend()
+	}
+}
+
+func (view *RequestView) setStatus(status string) {
+	view.status = status
+	view.renderFrame()
+}
+
+// setSummary shows the status bar of the response at idx next to the request
+// name, or removes it if idx is negative.
+func (view *RequestView) setSummary(idx int) {
+	view.summary = ""
+	view.status = ""
+	if idx >= 0 {
+		view.summary = view.history.responses[idx].summary(idx, len(view.history.responses))
+	}
+	view.renderFrame()
+}
+
+func (view *RequestView) renderFrame() {
+	view.frame.Clear()
+	header := tview.Escape(view.request.DisplayName())
+	if view.summary != "" {
+		header += "  [-]" + view.summary
+	}
+	view.frame.AddText(header, true, tview.AlignCenter, tcell.ColorForestGreen)
+	if view.status != "" {
+		view.frame.AddText(view.status, false, tview.AlignCenter, tcell.ColorYellow)
+	}
+}
+
 func (view *RequestView) showError(err error) {
 	view.main.SetBorder(false).SetTitle("Error!").SetTitleColor(tcell.ColorOrangeRed)
 	view.refreshContent = func() {
 		view.main.SetText(err.Error())
 	}
-	view.refreshContent()
+	view.refresh()
 	view.main.SetTextColor(tcell.ColorOrangeRed)
 	commands := []Command{
 		{
@@ -410,7 +839,7 @@ func (view *RequestView) showAssertions(idx int, previousView func(int)) {
 	view.main.SetDynamicColors(true)
 
 	view.refreshContent = func() {
-		resp := view.responses[idx]
+		resp := view.history.responses[idx]
 		builder := strings.Builder{}
 		builder.WriteString("[::bu]Pre-Request Assertions[::-]:\n")
 		writeAssertionResults(&builder, view.request.PreRequestAssertions...)
@@ -418,7 +847,7 @@ func (view *RequestView) showAssertions(idx int, previousView func(int)) {
 		writeAssertionResults(&builder, resp.PostRequestAssertions...)
 		view.main.SetText(builder.String())
 	}
-	view.refreshContent()
+	view.refresh()
 	commands := []Command{
 		{
 			Name: "Clear",
@@ -438,6 +867,119 @@ func (view *RequestView) showAssertions(idx int, previousView func(int)) {
 	view.registerCommands(append(view.baseCommands, commands...)...)
 }
 
+func (view *RequestView) showAttempts(idx int, previousView func(int)) {
+	view.main.SetBorder(false).SetTitle("Attempts").SetTitleColor(tcell.ColorYellowGreen)
+	view.main.SetDynamicColors(true)
+
+	view.refreshContent = func() {
+		view.main.SetText(formatAttempts(view.history.responses[idx].attempts))
+	}
+	view.refresh()
+	commands := []Command{
+		{
+			Name: "Clear",
+			Key:  tcell.KeyEscape,
+			Handler: func() {
+				previousView(idx)
+			},
+		},
+	}
+	view.registerCommands(append(view.baseCommands, commands...)...)
+}
+
+func (view *RequestView) showJSONTree(idx int) {
+	body, err := view.history.responses[idx].body()
+	if err != nil {
+		view.showError(err)
+		return
+	}
+	tree, err := NewJSONTreeView(view.app, view.request.DisplayName(), body, view)
+	if err != nil {
+		view.showError(err)
+		return
+	}
+	tree.Mount(view.app)
+}
+
+func (view *RequestView) showTiming(idx int, previousView func(int)) {
+	view.main.SetBorder(false).SetTitle("Timing").SetTitleColor(tcell.ColorYellowGreen)
+	view.main.SetDynamicColors(true)
+
+	view.refreshContent = func() {
+		attempts := view.history.responses[idx].attempts
+		if len(attempts) == 0 {
+			view.main.SetText("no timing recorded")
+			return
+		}
+		view.main.SetText(formatTiming(attempts[len(attempts)-1].Timing))
+	}
+	view.refresh()
+	commands := []Command{
+		{
+			Name: "Clear",
+			Key:  tcell.KeyEscape,
+			Handler: func() {
+				previousView(idx)
+			},
+		},
+	}
+	view.registerCommands(append(view.baseCommands, commands...)...)
+}
+
+// formatTiming renders the phases of a request as a waterfall, each bar
+// offset by when the phase started.
+func formatTiming(timing client.Timing) string {
+	const width = 60
+	colors := []string{"teal", "orange", "purple", "yellow", "green"}
+	scale := func(d time.Duration) int {
+		if timing.Total <= 0 {
+			return 0
+		}
+		return int(float64(width) * float64(d) / float64(timing.Total))
+	}
+	builder := strings.Builder{}
+	builder.WriteString("[::bu]Timing[::-]:\n")
+	for i, phase := range timing.Phases {
+		offset := min(scale(phase.Start), width-1)
+		length := min(max(scale(phase.Duration), 1), width-offset)
+		fmt.Fprintf(&builder, "%-20s %10s |%s[%s]%s[-]%s|\n",
+			phase.Name,
+			phase.Duration.Round(time.Microsecond),
+			strings.Repeat(" ", offset),
+			colors[i%len(colors)],
+			strings.Repeat("█", length),
+			strings.Repeat(" ", width-offset-length))
+	}
+	fmt.Fprintf(&builder, "%-20s %10s\n", "Total", timing.Total.Round(time.Microsecond))
+	if timing.ConnectionReused {
+		builder.WriteString("\n(connection reused)\n")
+	}
+	return builder.String()
+}
+
+func formatAttempts(attempts []client.Attempt) string {
+	builder := strings.Builder{}
+	builder.WriteString("[::bu]Attempts[::-]:\n")
+	for _, attempt := range attempts {
+		color := "green"
+		if attempt.Err != nil || attempt.StatusCode >= 500 {
+			color = "red"
+		}
+		fmt.Fprintf(&builder, "-- [%s]%s[-]\n", color, tview.Escape(attempt.String()))
+	}
+	return builder.String()
+}
+
+// formatPolls lists the responses received while polling a request.
+func formatPolls(polls []client.Poll) string {
+	builder := strings.Builder{}
+	builder.WriteString("[::bu]Polls[::-]:\n")
+	for _, poll := range polls {
+		fmt.Fprintf(&builder, "-- %s\n", tview.Escape(poll.String()))
+	}
+	return builder.String()
+}
+
 func (view *RequestView) showScripts(previousView func()) {
 	view.main.SetBorder(false).SetTitle("Assertions").SetTitleColor(tcell.ColorYellowGreen)
 	view.main.SetDynamicColors(true)
@@ -450,7 +992,7 @@ func (view *RequestView) showScripts(previousView func()) {
 		builder.WriteString(colorize(view.request.PostRequestScript, JavascriptLexer, AlternativeTheme, true))
 		view.main.SetText(builder.String())
 	}
-	view.refreshContent()
+	view.refresh()
 	commands := []Command{
 		{
 			Name: "Clear",
@@ -470,7 +1012,7 @@ func (view *RequestView) showLogs(previousView func()) {
 	view.refreshContent = func() {
 		view.main.SetText(strings.Join(view.request.Logs, "\n"))
 	}
-	view.refreshContent()
+	view.refresh()
 	commands := []Command{
 		{
 			Name: "Clear",
@@ -497,8 +1039,18 @@ func writeAssertionResults(writer io.Writer, assertions ...rq.Assertion) {
 }
 
 func (view *RequestView) registerCommands(commands ...Command) {
+	view.commands = commands
+	view.renderCommands()
+}
+
+// renderCommands lists the registered commands in the command bar. Commands
+// without a name are not listed.
+func (view *RequestView) renderCommands() {
 	var parts []string
-	for _, command := range commands {
+	for _, command := range view.commands {
+		if command.Name == "" {
+			continue
+		}
 		if command.Rune != 0 {
 			parts = append(parts, fmt.Sprintf("%s (%s)", command.Name, string(command.Rune)))
 			continue
@@ -506,12 +1058,15 @@ func (view *RequestView) registerCommands(commands ...Command) {
 		parts = append(parts, fmt.Sprintf("%s (%s)", command.Name, tcell.KeyNames[command.Key]))
 
 	}
+	if status := view.search.status(); status != "" {
+		parts = append(parts, status)
+	}
 	view.commandsView.SetText(strings.Join(parts, " | "))
-	view.commands = commands
 }
 
 func (view *RequestView) Mount(app *tview.Application) {
-	view.refreshContent()
+	view.renderFrame()
+	view.refresh()
 	app.SetRoot(view.frame, true)
 }
 
@@ -528,6 +1083,21 @@ func (view *RequestView) setEnvironment(ctx context.Context, text string) {
 	}
 }
 
+func setSettings(ctx context.Context, text string) error {
+	settings := client.GetClient(ctx).Settings
+	for _, line := range strings.Split(text, "\n") {
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		if err := settings.Set(strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])); err != nil {
+			return err
+		}
+	}
+	client.GetClient(ctx).Settings = settings
+	return nil
+}
+
 func getEnvironmentText(ctx context.Context) string {
 	env := rq.GetEnvironment(ctx)
 	builder := strings.Builder{}
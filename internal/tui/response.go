@@ -0,0 +1,222 @@
+package tui
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"net/http/httputil"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-rq/req/internal/client"
+	"github.com/go-rq/req/internal/pretty"
+	"github.com/go-rq/req/internal/query"
+	"github.com/go-rq/req/internal/template"
+	"github.com/go-rq/rq"
+	"github.com/rivo/tview"
+)
+
+type Response struct {
+	cachedPrettyString string
+	cachedRawString    string
+	cachedBody         []byte
+	cachedDocument     any
+	attempts           []client.Attempt
+	variables          []template.Variable
+	rq.Response
+}
+
+// prettyString returns the status line and headers followed by the body
+// formatted and highlighted according to its content type.
+func (p *Response) prettyString() (string, error) {
+	if p.cachedPrettyString == "" {
+		body, err := p.body()
+		if err != nil {
+			return "", err
+		}
+		head := strings.Builder{}
+		fmt.Fprintf(&head, "%s %s\n", p.Proto, p.Status)
+		keys := make([]string, 0, len(p.Header))
+		for key := range p.Header {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			for _, value := range p.Header[key] {
+				fmt.Fprintf(&head, "%s: %s\n", key, value)
+			}
+		}
+		text := colorize(head.String(), HTTPLexer, DefaultTheme, true)
+		contentType := p.Header.Get("Content-Type")
+		if pretty.IsBinary(contentType, body) {
+			text += "\n" + binaryPreview(contentType, body)
+		} else if len(body) > 0 {
+			formatted, lexer := pretty.Format(contentType, body)
+			text += "\n" + colorize(formatted, lexer, DefaultTheme, true)
+		}
+		p.cachedPrettyString = text
+	}
+	return p.cachedPrettyString, nil
+}
+
+// rawString returns the response as received. Binary bodies are replaced by
+// a preview.
+func (p *Response) rawString() string {
+	if p.cachedRawString == "" {
+		body, err := p.body()
+		contentType := p.Header.Get("Content-Type")
+		if err == nil && pretty.IsBinary(contentType, body) {
+			head, _ := httputil.DumpResponse(p.Response.Response, false)
+			p.cachedRawString = colorize(string(head), HTTPLexer, DefaultTheme, true) + binaryPreview(contentType, body)
+		} else {
+			p.cachedRawString = colorize(p.Response.String(), HTTPLexer, DefaultTheme, true)
+		}
+		// String leaves the whole response in the body
+		p.body()
+	}
+
+	return p.cachedRawString
+}
+
+// body returns the response body, leaving it in place to be read again.
+func (p *Response) body() ([]byte, error) {
+	if p.cachedBody == nil {
+		body, err := io.ReadAll(p.Body)
+		if err != nil {
+			return nil, err
+		}
+		p.Body.Close()
+		p.Body = io.NopCloser(bytes.NewReader(body))
+		p.cachedBody = body
+	}
+	p.Body = io.NopCloser(bytes.NewReader(p.cachedBody))
+	return p.cachedBody, nil
+}
+
+// filtered returns the JSON body filtered with the jq or JSONPath expression.
+func (p *Response) filtered(expr string) (string, error) {
+	if p.cachedDocument == nil {
+		body, err := p.body()
+		if err != nil {
+			return "", err
+		}
+		document, err := query.Decode(body)
+		if err != nil {
+			return "", fmt.Errorf("response body is not valid JSON: %w", err)
+		}
+		p.cachedDocument = document
+	}
+	q, err := query.Compile(expr)
+	if err != nil {
+		return "", err
+	}
+	results, err := q.Run(p.cachedDocument)
+	if err != nil {
+		return "", err
+	}
+	text, err := query.Format(results)
+	if err != nil {
+		return "", err
+	}
+	return colorize(text, pretty.JSONLexer, DefaultTheme, true), nil
+}
+
+// filename returns the name to save the body under, taken from the
+// Content-Disposition header or else the request URL.
+func (p *Response) filename() string {
+	if _, params, err := mime.ParseMediaType(p.Header.Get("Content-Disposition")); err == nil && params["filename"] != "" {
+		return filepath.Base(params["filename"])
+	}
+	name := "response"
+	if p.Request != nil {
+		if base := path.Base(p.Request.URL.Path); base != "/" && base != "." {
+			name = base
+		}
+	}
+	if path.Ext(name) == "" {
+		if mediaType, _, err := mime.ParseMediaType(p.Header.Get("Content-Type")); err == nil {
+			if extensions, _ := mime.ExtensionsByType(mediaType); len(extensions) > 0 {
+				name += extensions[0]
+			}
+		}
+	}
+	return name
+}
+
+// summary returns the status bar shown in the frame of the response views.
+func (p *Response) summary(idx, count int) string {
+	color := "green"
+	switch {
+	case p.StatusCode >= 500:
+		color = "red"
+	case p.StatusCode >= 400:
+		color = "yellow"
+	case p.StatusCode >= 300:
+		color = "teal"
+	}
+	parts := []string{fmt.Sprintf("[%s::b]%s[-::-]", color, tview.Escape(p.Status))}
+	if len(p.attempts) > 0 {
+		parts = append(parts, p.attempts[len(p.attempts)-1].Duration.Round(time.Millisecond).String())
+	}
+	if body, err := p.body(); err == nil {
+		parts = append(parts, formatSize(len(body)))
+	}
+	if contentType := p.Header.Get("Content-Type"); contentType != "" {
+		parts = append(parts, tview.Escape(contentType))
+	}
+	parts = append(parts, fmt.Sprintf("response %d/%d", idx+1, count))
+	return strings.Join(parts, " | ")
+}
+
+func formatSize(size int) string {
+	switch {
+	case size >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(size)/(1<<20))
+	case size >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(size)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", size)
+	}
+}
+
+// variablesSection returns the values the dynamic expressions of the request
+// resolved to, or an empty string if it had none.
+func (p *Response) variablesSection() string {
+	if len(p.variables) == 0 {
+		return ""
+	}
+	builder := strings.Builder{}
+	builder.WriteString("[::bu]Variables[::-]:\n")
+	for _, variable := range p.variables {
+		fmt.Fprintf(&builder, "-- [yellow]{{%s}}[-] = %s\n", tview.Escape(variable.Name), tview.Escape(variable.Value))
+	}
+	builder.WriteString("\n")
+	return builder.String()
+}
+
+// redirectChain returns the redirects followed to get the response, or an
+// empty string if there were none.
+func (p *Response) redirectChain() string {
+	if len(p.attempts) == 0 {
+		return ""
+	}
+	redirects := p.attempts[len(p.attempts)-1].Redirects
+	if len(redirects) == 0 {
+		return ""
+	}
+	builder := strings.Builder{}
+	builder.WriteString("[::bu]Redirects[::-]:\n")
+	for _, redirect := range redirects {
+		fmt.Fprintf(&builder, "-- [yellow]%s[-] %s %s -> %s\n",
+			tview.Escape(redirect.Status),
+			redirect.Method,
+			tview.Escape(redirect.URL),
+			tview.Escape(redirect.Location))
+	}
+	builder.WriteString("\n")
+	return builder.String()
+}
@@ -0,0 +1,223 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/go-rq/req/internal/client"
+	"github.com/go-rq/req/internal/httpfile"
+	"github.com/go-rq/rq"
+	"github.com/rivo/tview"
+)
+
+const (
+	runAllHelp     = "Enter: Open, Esc: Cancel"
+	runAllDoneHelp = "Enter: Open, r: Run again, Esc: Back"
+)
+
+// RunAllView sends every request of a file in order, sharing the
+// environment so variables set by the scripts of a request are available to
+// the following ones, and shows the results in a table as they arrive.
+type RunAllView struct {
+	app          *tview.Application
+	context      context.Context
+	table        *tview.Table
+	helpInfo     *tview.TextView
+	layout       *tview.Frame
+	previousView View
+	requests     []httpfile.Request
+	cancelRun    context.CancelFunc
+}
+
+func NewRunAllView(ctx context.Context, app *tview.Application, requests []httpfile.Request, previousView View) *RunAllView {
+	view := &RunAllView{
+		app:          app,
+		context:      ctx,
+		table:        tview.NewTable(),
+		helpInfo:     tview.NewTextView().SetDynamicColors(true),
+		previousView: previousView,
+		requests:     requests,
+	}
+	view.table.SetBorder(true).SetTitle("Results")
+	view.table.SetSelectable(true, false).SetFixed(1, 0)
+	view.table.SetSelectedFunc(func(row, _ int) {
+		if row > 0 {
+			view.open(row - 1)
+		}
+	})
+	view.helpInfo.SetTextAlign(tview.AlignCenter)
+	grid := tview.NewGrid().SetRows(0, 2)
+	grid.AddItem(view.table, 0, 0, 1, 1, 0, 0, true)
+	grid.AddItem(view.helpInfo, 1, 0, 1, 1, 0, 0, false)
+	view.layout = tview.NewFrame(grid)
+	view.layout.AddText("Run All", true, tview.AlignCenter, tcell.ColorForestGreen)
+	view.table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEsc:
+			if view.cancelRun != nil {
+				view.cancelRun()
+				return nil
+			}
+			view.previousView.Mount(view.app)
+			return nil
+		case event.Key() == tcell.KeyRune && event.Rune() == 'r':
+			if view.cancelRun == nil {
+				view.run()
+			}
+			return nil
+		}
+		return event
+	})
+	return view
+}
+
+// run sends the requests one after another in the background.
+func (view *RunAllView) run() {
+	ctx, cancel := context.WithCancel(view.context)
+	view.cancelRun = cancel
+	view.table.Clear()
+	for col, title := range []string{"#", "Request", "Status", "Duration", "Assertions", "Details"} {
+		view.table.SetCell(0, col, tview.NewTableCell(title).SetSelectable(false).SetAttributes(tcell.AttrBold))
+	}
+	for i, request := range view.requests {
+		view.setRow(i, request, "[::d]pending", "", "", "")
+	}
+	view.table.Select(1, 0)
+	view.helpInfo.SetText(runAllHelp)
+	start := time.Now()
+
+	go func() {
+		var passed, failed int
+		for i := range view.requests {
+			i := i
+			request := view.requests[i]
+			view.app.QueueUpdateDraw(func() {
+				view.setRow(i, request, "[yellow]running", "", "", "")
+			})
+			pollCtx := client.WithPollFunc(ctx, func(poll client.Poll) {
+				view.app.QueueUpdateDraw(func() {
+					view.setRow(i, request, fmt.Sprintf("[yellow]polling #%d", poll.Number), "", "", tview.Escape(poll.String()))
+				})
+			})
+			result, err := client.GetClient(ctx).Send(pollCtx, &request)
+			assertions, ok := formatAssertions(request.PreRequestAssertions, result.Response)
+			ok = ok && (err == nil || errors.Is(err, rq.ErrSkipped))
+			view.app.QueueUpdateDraw(func() {
+				recordPrerequisites(view.context, &request, result.Prerequisites)
+				recordPolls(view.context, &request, result.Polls, err == nil)
+				view.requests[i] = request
+				if err == nil && result.Response != nil {
+					history := getHistory(view.context).get(request)
+					history.add(Response{Response: *result.Response, attempts: result.Attempts, variables: result.Variables})
+				}
+				view.setResult(i, request, result, err, assertions)
+			})
+			if ok {
+				passed++
+			} else {
+				failed++
+			}
+			if ctx.Err() != nil {
+				break
+			}
+		}
+		view.app.QueueUpdateDraw(func() {
+			view.cancelRun = nil
+			cancel()
+			summary := fmt.Sprintf("[green]%d passed[-]", passed)
+			if failed > 0 {
+				summary += fmt.Sprintf(", [red]%d failed[-]", failed)
+			}
+			if skipped := len(view.requests) - passed - failed; skipped > 0 {
+				summary += fmt.Sprintf(", %d not run", skipped)
+			}
+			view.helpInfo.SetText(fmt.Sprintf("%s in %s\n%s", summary, time.Since(start).Round(time.Millisecond), runAllDoneHelp))
+		})
+	}()
+}
+
+func (view *RunAllView) setRow(i int, request httpfile.Request, status, duration, assertions, details string) {
+	row := i + 1
+	view.table.SetCell(row, 0, tview.NewTableCell(fmt.Sprintf("%d", row)).SetAlign(tview.AlignRight))
+	view.table.SetCell(row, 1, tview.NewTableCell(tview.Escape(request.DisplayName())))
+	view.table.SetCell(row, 2, tview.NewTableCell(status))
+	view.table.SetCell(row, 3, tview.NewTableCell(duration).SetAlign(tview.AlignRight))
+	view.table.SetCell(row, 4, tview.NewTableCell(assertions))
+	view.table.SetCell(row, 5, tview.NewTableCell(details).SetExpansion(1))
+}
+
+// setResult fills the row of the request with the outcome of sending it.
+func (view *RunAllView) setResult(i int, request httpfile.Request, result *client.Result, err error, assertions string) {
+	var duration string
+	if len(result.Attempts) > 0 {
+		duration = result.Attempts[len(result.Attempts)-1].Duration.Round(time.Millisecond).String()
+	}
+	switch {
+	case errors.Is(err, rq.ErrSkipped):
+		view.setRow(i, request, "[yellow]skipped", duration, assertions, "")
+	case err != nil:
+		view.setRow(i, request, "[red]error", duration, assertions, "[red]"+tview.Escape(err.Error()))
+	default:
+		color := "green"
+		switch {
+		case result.Response.StatusCode >= 500:
+			color = "red"
+		case result.Response.StatusCode >= 400:
+			color = "yellow"
+		case result.Response.StatusCode >= 300:
+			color = "teal"
+		}
+		var details []string
+		if count := len(result.Polls); count > 0 {
+			details = append(details, fmt.Sprintf("condition true at poll #%d", count))
+		}
+		if result.Output != "" {
+			details = append(details, "saved body to "+tview.Escape(result.Output))
+		}
+		view.setRow(i, request, fmt.Sprintf("[%s]%s", color, tview.Escape(result.Response.Status)), duration, assertions, strings.Join(details, ", "))
+	}
+}
+
+// formatAssertions summarizes the pre and post request assertions of a
+// request and reports whether they all passed.
+func formatAssertions(pre []rq.Assertion, resp *rq.Response) (string, bool) {
+	assertions := pre
+	if resp != nil {
+		assertions = append(assertions[:len(assertions):len(assertions)], resp.PostRequestAssertions...)
+	}
+	if len(assertions) == 0 {
+		return "[::d]none", true
+	}
+	failed := 0
+	for _, assertion := range assertions {
+		if !assertion.Success {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Sprintf("[red]%d/%d failed", failed, len(assertions)), false
+	}
+	return fmt.Sprintf("[green]%d/%d passed", len(assertions), len(assertions)), true
+}
+
+// open shows the request in a RequestView, starting at its latest response.
+func (view *RunAllView) open(i int) {
+	request := view.requests[i]
+	requestView := NewRequestView(view.context, view.app, request, view)
+	requestView.Mount(view.app)
+	if count := len(requestView.history.responses); count > 0 {
+		requestView.showPrettyResponse(count - 1)
+	}
+}
+
+func (view *RunAllView) Mount(app *tview.Application) {
+	if view.table.GetRowCount() == 0 {
+		view.run()
+	}
+	app.SetRoot(view.layout, true)
+	app.SetFocus(view.table)
+}
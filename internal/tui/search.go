@@ -0,0 +1,158 @@
+package tui
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+var (
+	// styleTagRegexp and regionTagRegexp match the tags tview interprets in
+	// the text of a TextView, escapedTagRegexp an escaped tag such as "[red[]".
+	styleTagRegexp   = regexp.MustCompile(`^\[[a-zA-Z0-9_,;:\-\.#]+\]`)
+	regionTagRegexp  = regexp.MustCompile(`^\["[a-zA-Z0-9_,;: \-\.]*"\]`)
+	escapedTagRegexp = regexp.MustCompile(`^\[[^\[\]]+\[+\]`)
+)
+
+// search is the state of an incremental search in the request view.
+type search struct {
+	term string
+
+	// source is the text of the current screen before the matches were marked
+	source  string
+	matches int
+	current int
+}
+
+func (s search) status() string {
+	if s.term == "" {
+		return ""
+	}
+	if s.matches == 0 {
+		return fmt.Sprintf("No matches for %q", s.term)
+	}
+	return fmt.Sprintf("Match %d/%d (n/N)", s.current+1, s.matches)
+}
+
+// editSearch shows an input for the search term. Matches are highlighted as
+// the term is typed; Enter keeps the search and Esc clears it.
+func (view *RequestView) editSearch() {
+	view.prompt.SetChangedFunc(nil)
+	view.prompt.SetLabel("Search: ").SetText(view.search.term)
+	view.prompt.SetChangedFunc(func(text string) {
+		view.search.term = text
+		view.search.current = 0
+		view.applySearch()
+	})
+	view.prompt.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEscape {
+			view.search.term = ""
+			view.applySearch()
+		}
+		view.setPromptVisible(false)
+	})
+	view.setPromptVisible(true)
+}
+
+// nextMatch moves the highlight by delta matches, wrapping around at either
+// end of the text.
+func (view *RequestView) nextMatch(delta int) {
+	if view.search.matches == 0 {
+		return
+	}
+	view.search.current = (view.search.current + delta + view.search.matches) % view.search.matches
+	view.highlightMatch()
+}
+
+// applySearch marks the matches of the search term on the current screen.
+func (view *RequestView) applySearch() {
+	text, matches := markMatches(view.search.source, view.main.GetText(true), view.search.term)
+	view.search.matches = matches
+	if view.search.current >= matches {
+		view.search.current = 0
+	}
+	view.main.SetText(text)
+	view.highlightMatch()
+}
+
+func (view *RequestView) highlightMatch() {
+	if view.search.matches == 0 {
+		view.main.Highlight()
+	} else {
+		view.main.Highlight(fmt.Sprintf("match-%d", view.search.current)).ScrollToHighlight()
+	}
+	view.renderCommands()
+}
+
+// markMatches wraps every case-insensitive match of term in the text in a
+// region so it can be highlighted. Matches are found in the text as it is
+// displayed, i.e. without its style tags. If the tags cannot be mapped back
+// onto the displayed text, the plain text is marked instead, losing its
+// styles.
+func markMatches(tagged, plain, term string) (string, int) {
+	if term == "" {
+		return tagged, 0
+	}
+	visible, positions := visibleText(tagged)
+	if visible != plain {
+		tagged = tview.Escape(plain)
+		visible, positions = visibleText(tagged)
+	}
+	matches := regexp.MustCompile("(?i)"+regexp.QuoteMeta(term)).FindAllStringIndex(visible, -1)
+	if len(matches) == 0 {
+		return tagged, 0
+	}
+	builder := strings.Builder{}
+	last := 0
+	for i, match := range matches {
+		start, end := positions[match[0]], positions[match[1]]
+		builder.WriteString(tagged[last:start])
+		fmt.Fprintf(&builder, `["match-%d"]`, i)
+		builder.WriteString(tagged[start:end])
+		builder.WriteString(`[""]`)
+		last = end
+	}
+	builder.WriteString(tagged[last:])
+	return builder.String(), len(matches)
+}
+
+// visibleText strips the tags from the text. The returned positions map each
+// byte offset of the stripped text, and its length, to the offset in the
+// tagged text.
+func visibleText(tagged string) (string, []int) {
+	var (
+		visible   strings.Builder
+		positions []int
+	)
+	for i := 0; i < len(tagged); {
+		if tagged[i] == '[' {
+			rest := tagged[i:]
+			if match := escapedTagRegexp.FindString(rest); match != "" {
+				// "[red[]" is displayed as "[red]"
+				shown := match[:len(match)-2] + "]"
+				for j := 0; j < len(shown); j++ {
+					positions = append(positions, i)
+				}
+				visible.WriteString(shown)
+				i += len(match)
+				continue
+			}
+			if match := regionTagRegexp.FindString(rest); match != "" {
+				i += len(match)
+				continue
+			}
+			if match := styleTagRegexp.FindString(rest); match != "" {
+				i += len(match)
+				continue
+			}
+		}
+		positions = append(positions, i)
+		visible.WriteByte(tagged[i])
+		i++
+	}
+	positions = append(positions, len(tagged))
+	return visible.String(), positions
+}
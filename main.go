@@ -6,32 +6,52 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
+	"github.com/go-rq/req/internal/client"
+	"github.com/go-rq/req/internal/httpfile"
 	"github.com/go-rq/req/internal/tui"
 	"github.com/go-rq/rq"
 	"github.com/rivo/tview"
 )
 
-var envFilePath string
+var (
+	envFilePath       string
+	configFilePath    string
+	cookieJarPath     string
+	settings          = client.DefaultSettings()
+	transportSettings client.TransportSettings
+)
 
 func init() {
 	initEnvFileFlags()
+	initConfigFileFlags()
+	initClientFlags()
+	initTransportFlags()
 }
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "run":
+			os.Exit(runCommand(os.Args[2:]))
+		case "bench":
+			os.Exit(benchCommand(os.Args[2:]))
+		}
+	}
 	flag.Parse()
-	app := tview.NewApplication()
-	ctx := context.Background()
-	env := map[string]string{}
-	if envFilePath != "" {
-		var err error
-		env, err = loadEnvFile(envFilePath)
-		if err != nil {
+	if configFilePath != "" {
+		if err := loadConfigFile(flag.CommandLine, configFilePath); err != nil {
 			panic(err)
 		}
 	}
-	ctx = rq.WithEnvironment(ctx, env)
+	ctx, err := newContext()
+	if err != nil {
+		panic(err)
+	}
+	app := tview.NewApplication()
+	ctx = tui.WithHistory(ctx, tui.NewHistory())
 	path := "."
 	if flag.NArg() > 1 {
 		path = flag.Arg(0)
@@ -44,37 +64,157 @@ func main() {
 	}
 }
 
+// newContext returns a context holding the environment and the client
+// configured by the flags.
+func newContext() (context.Context, error) {
+	ctx := context.Background()
+	env := map[string]string{}
+	if envFilePath != "" {
+		var err error
+		env, err = loadEnvFile(envFilePath)
+		if err != nil {
+			return nil, err
+		}
+	}
+	ctx = rq.WithEnvironment(ctx, env)
+	jar, err := client.NewJar(cookieJarPath)
+	if err != nil {
+		return nil, err
+	}
+	c, err := client.New(settings, transportSettings, jar)
+	if err != nil {
+		return nil, err
+	}
+	return client.WithClient(ctx, c), nil
+}
+
 func selectFile(ctx context.Context, app *tview.Application, prevView tui.View) func(string) {
 	return func(path string) {
 		rv := tui.NewRequestSelectView(app, path, prevView)
 		rv.SetCallback(selectRequest(ctx, app, rv))
+		rv.SetRunAllCallback(runAll(ctx, app, rv))
 		rv.Mount(app)
 	}
 }
 
-func selectRequest(ctx context.Context, app *tview.Application, prevView tui.View) func(request rq.Request) {
-	return func(request rq.Request) {
+func selectRequest(ctx context.Context, app *tview.Application, prevView tui.View) func(request httpfile.Request) {
+	return func(request httpfile.Request) {
 		rv := tui.NewRequestView(ctx, app, request, prevView)
 		rv.Mount(app)
 	}
 }
 
+func runAll(ctx context.Context, app *tview.Application, prevView tui.View) func(requests []httpfile.Request) {
+	return func(requests []httpfile.Request) {
+		tui.NewRunAllView(ctx, app, requests, prevView).Mount(app)
+	}
+}
+
 func initEnvFileFlags() {
 	const usage = "path to .env file"
 	flag.StringVar(&envFilePath, "env", "", usage)
 	flag.StringVar(&envFilePath, "e", "", usage+" (shorthand)")
 }
 
+func initClientFlags() {
+	flag.DurationVar(&settings.Timeout, "timeout", settings.Timeout, "timeout for each attempt at sending a request, e.g. 10s (0 disables the timeout)")
+	flag.IntVar(&settings.Retries, "retries", settings.Retries, "number of times a failed request is retried")
+	flag.Var(&settings.RetryOn, "retry-on", "comma separated failures to retry: 5xx, connect, timeout")
+	flag.DurationVar(&settings.Backoff, "backoff", settings.Backoff, "delay before the first retry, doubled for each following retry")
+	flag.StringVar(&cookieJarPath, "cookie-jar", "", "path to a file the session cookies are loaded from and saved to, e.g. one per environment")
+	flag.IntVar(&settings.MaxRedirects, "max-redirects", settings.MaxRedirects, "number of redirects followed before giving up")
+	flag.DurationVar(&settings.DependencyTTL, "dependency-ttl", settings.DependencyTTL, "how long the response of a request is reused for requests depending on it with @depends-on (0 sends it every time)")
+	flag.BoolVar(&settings.SendReferences, "send-references", settings.SendReferences, "send requests referenced with {{Name.response...}} that have no response yet")
+}
+
+func initConfigFileFlags() {
+	const usage = "path to a config file of flag=value lines, flags given on the command line take precedence"
+	flag.StringVar(&configFilePath, "config", "", usage)
+}
+
+func initTransportFlags() {
+	flag.StringVar(&transportSettings.Proxy, "proxy", "", "URL of the HTTP(S) proxy to send requests through")
+	flag.StringVar(&transportSettings.CACert, "cacert", "", "path to a PEM file of additional CA certificates to trust")
+	flag.StringVar(&transportSettings.Cert, "cert", "", "path to a PEM client certificate for mutual TLS")
+	flag.StringVar(&transportSettings.Key, "key", "", "path to the PEM private key of the client certificate")
+	flag.BoolVar(&transportSettings.Insecure, "insecure", false, "skip verification of server certificates")
+	flag.BoolVar(&transportSettings.Insecure, "k", false, "skip verification of server certificates (shorthand)")
+	flag.StringVar(&transportSettings.TLSMinVersion, "tls-min-version", "", "minimum TLS version: 1.0, 1.1, 1.2 or 1.3")
+	flag.StringVar(&transportSettings.ServerName, "sni", "", "server name to send with SNI and verify the server certificate against")
+}
+
+// configAliases maps the shorthand flags to the flag they set, so a config
+// entry for either does not override the other given on the command line.
+var configAliases = map[string]string{
+	"e": "env",
+	"k": "insecure",
+}
+
+// configPaths are the flags taking a path, which is relative to the
+// directory of the config file when given in it.
+var configPaths = map[string]bool{
+	"env":        true,
+	"cookie-jar": true,
+	"cacert":     true,
+	"cert":       true,
+	"key":        true,
+}
+
+// loadConfigFile sets the flags listed in the config file that were not
+// given on the command line.
+func loadConfigFile(flags *flag.FlagSet, path string) error {
+	config, err := loadEnvFile(path)
+	if err != nil {
+		return err
+	}
+	// a shorthand and its flag cannot both be given, which of them wins would
+	// depend on the order of the map
+	names := map[string]string{}
+	for name := range config {
+		name = strings.TrimSpace(name)
+		if other, ok := names[canonicalFlag(name)]; ok {
+			first, second := min(name, other), max(name, other)
+			return fmt.Errorf("%s: %s and %s set the same flag, keep one of them", path, first, second)
+		}
+		names[canonicalFlag(name)] = name
+	}
+	set := map[string]bool{}
+	flags.Visit(func(f *flag.Flag) {
+		set[canonicalFlag(f.Name)] = true
+	})
+	for name, value := range config {
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+		if set[canonicalFlag(name)] {
+			continue
+		}
+		if configPaths[canonicalFlag(name)] && value != "" && !filepath.IsAbs(value) {
+			value = filepath.Join(filepath.Dir(path), value)
+		}
+		if err := flags.Set(name, value); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+func canonicalFlag(name string) string {
+	if alias, ok := configAliases[name]; ok {
+		return alias
+	}
+	return name
+}
+
 func loadEnvFile(path string) (map[string]string, error) {
 	env := map[string]string{}
 	file, err := os.Open(path)
 	if err != nil {
 		return nil, err
 	}
+	defer file.Close()
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
 		line := scanner.Text()
-		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(strings.TrimSpace(line), "#") {
 			continue
 		}
 		parts := strings.SplitN(line, "=", 2)
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"regexp"
+	"strings"
+
+	"github.com/go-rq/req/internal/runner"
+)
+
+// runCommand implements `req run`, which sends the requests of the given
+// files and directories without the TUI and prints a report. It returns the
+// exit code: 1 if a request failed and 2 if the run could not be started.
+func runCommand(args []string) int {
+	flags := newFlagSet("run", "[flags] [file or directory...]")
+	var dataPath string
+	var options runner.Options
+	flags.StringVar(&dataPath, "data", "", "path to a CSV or JSON data set, the requests of every file are run once per row")
+	flags.IntVar(&options.Parallel, "parallel", 1, "number of files run at the same time, each with a copy of the environment")
+	var namePattern string
+	flags.Var((*listFlag)(&options.Filter.Tags), "tag", "comma separated tags, only requests with one of them are run")
+	flags.Var((*listFlag)(&options.Filter.SkipTags), "skip-tag", "comma separated tags, requests with one of them are not run")
+	flags.StringVar(&namePattern, "name-regex", "", "regular expression the names of the requests run must match")
+	flags.Var((*listFlag)(&options.Filter.Methods), "method", "comma separated HTTP methods, only requests with one of them are run")
+	paths, err := parseFlags(flags, args)
+	if err != nil {
+		return fail("run", err)
+	}
+	if namePattern != "" {
+		if options.Filter.Name, err = regexp.Compile(namePattern); err != nil {
+			return fail("run", err)
+		}
+	}
+	ctx, err := newContext()
+	if err != nil {
+		return fail("run", err)
+	}
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+	if dataPath != "" {
+		if options.Data, err = runner.LoadData(dataPath); err != nil {
+			return fail("run", err)
+		}
+	}
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+	files, err := runner.Files(paths)
+	if err != nil {
+		return fail("run", err)
+	}
+	report := runner.Run(ctx, files, options)
+	report.Write(os.Stdout)
+	if !report.Passed() {
+		return 1
+	}
+	return 0
+}
+
+// newFlagSet returns the flags of a subcommand, which take the flags of req
+// in addition to their own.
+func newFlagSet(command, arguments string) *flag.FlagSet {
+	flags := flag.NewFlagSet(command, flag.ExitOnError)
+	flag.VisitAll(func(f *flag.Flag) {
+		flags.Var(f.Value, f.Name, f.Usage)
+	})
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage of req %s:\n  req %s %s\n", command, command, arguments)
+		flags.PrintDefaults()
+	}
+	return flags
+}
+
+// parseFlags parses the flags of a subcommand, which may come before or
+// after its arguments, loads the config file and returns the arguments.
+func parseFlags(flags *flag.FlagSet, args []string) ([]string, error) {
+	var arguments []string
+	for {
+		flags.Parse(args)
+		if flags.NArg() == 0 {
+			break
+		}
+		arguments = append(arguments, flags.Arg(0))
+		args = flags.Args()[1:]
+	}
+	if configFilePath != "" {
+		if err := loadConfigFile(flags, configFilePath); err != nil {
+			return nil, err
+		}
+	}
+	return arguments, nil
+}
+
+// listFlag is a flag of comma separated values that may be repeated.
+type listFlag []string
+
+func (l *listFlag) String() string {
+	if l == nil {
+		return ""
+	}
+	return strings.Join(*l, ",")
+}
+
+func (l *listFlag) Set(value string) error {
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			*l = append(*l, part)
+		}
+	}
+	return nil
+}
+
+func fail(command string, err error) int {
+	fmt.Fprintf(os.Stderr, "req %s: %s\n", command, err)
+	return 2
+}